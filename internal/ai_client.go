@@ -8,11 +8,11 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/andreim2k/aiterm/config"
+	"github.com/andreim2k/aiterm/internal/i18n"
 	"github.com/andreim2k/aiterm/logger"
 )
 
@@ -26,24 +26,79 @@ type AiClient struct {
 	config    *config.Config
 	configMgr *Manager // To access model configuration methods
 	client    *http.Client
+	backends  *BackendConfigLoader // Optional YAML-defined model profiles
+	history   *HistoryIndexer      // Optional RAG index over prior shell history
+	usage     *UsageTracker        // Optional per-session token/cost accounting
 }
 
 // Message represents a chat message
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// Tool describes a function the model may call, in OpenAI function-calling format.
+type Tool struct {
+	Type     string       `json:"type"` // currently always "function"
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes the callable surface of a Tool.
+type ToolFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// ToolCall represents a single function call requested by the model.
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"` // currently always "function"
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction carries the name and JSON-encoded arguments of a ToolCall.
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
 
 // ChatCompletionRequest represents a request to the chat completion API
 type ChatCompletionRequest struct {
-	Model    string    `json:"model,omitempty"`
-	Messages []Message `json:"messages"`
+	Model          string                    `json:"model,omitempty"`
+	Messages       []Message                 `json:"messages"`
+	Stream         bool                      `json:"stream,omitempty"`
+	Tools          []Tool                    `json:"tools,omitempty"`
+	ResponseFormat *ChatResponseFormatSchema `json:"response_format,omitempty"`
+	Temperature    *float64                  `json:"temperature,omitempty"`
+	TopP           *float64                  `json:"top_p,omitempty"`
+	MaxTokens      *int                      `json:"max_tokens,omitempty"`
+	Stop           []string                  `json:"stop,omitempty"`
+	Seed           *int                      `json:"seed,omitempty"`
+}
+
+// ChatResponseFormatSchema requests structured JSON output conforming to a
+// JSON schema from chat-completions providers that support it.
+type ChatResponseFormatSchema struct {
+	Type       string         `json:"type"` // "json_schema"
+	JSONSchema JSONSchemaSpec `json:"json_schema"`
+}
+
+// JSONSchemaSpec is the schema payload shared by ChatResponseFormatSchema and
+// ResponseRequest.Text.
+type JSONSchemaSpec struct {
+	Name   string                 `json:"name"`
+	Strict bool                   `json:"strict,omitempty"`
+	Schema map[string]interface{} `json:"schema"`
 }
 
 // ChatCompletionChoice represents a choice in the chat completion response
 type ChatCompletionChoice struct {
-	Index   int     `json:"index"`
-	Message Message `json:"message"`
+	Index        int     `json:"index"`
+	Message      Message `json:"message"`
+	FinishReason string  `json:"finish_reason,omitempty"`
 }
 
 // ChatCompletionResponse represents a response from the chat completion API
@@ -52,6 +107,7 @@ type ChatCompletionResponse struct {
 	Object  string                 `json:"object"`
 	Created int64                  `json:"created"`
 	Choices []ChatCompletionChoice `json:"choices"`
+	Usage   OpenAIUsage            `json:"usage"`
 }
 
 // Responses API Types
@@ -84,8 +140,12 @@ type ResponseRequest struct {
 	Tools              []interface{}          `json:"tools,omitempty"`
 	PreviousResponseID string                 `json:"previous_response_id,omitempty"`
 	Store              bool                   `json:"store,omitempty"`
+	Stream             bool                   `json:"stream,omitempty"`
 	Include            []string               `json:"include,omitempty"`
 	Text               map[string]interface{} `json:"text,omitempty"` // for structured outputs
+	Temperature        *float64               `json:"temperature,omitempty"`
+	TopP               *float64               `json:"top_p,omitempty"`
+	MaxOutputTokens    *int                   `json:"max_output_tokens,omitempty"`
 }
 
 // Response represents a response from the Responses API
@@ -119,7 +179,8 @@ func NewAiClient(cfg *config.Config) *AiClient {
 	return &AiClient{
 		config: cfg,
 		client: &http.Client{
-			Timeout: DefaultHTTPTimeout,
+			Timeout:   DefaultHTTPTimeout,
+			Transport: newRetryTransport(DefaultRetryConfig()),
 		},
 	}
 }
@@ -129,8 +190,50 @@ func (c *AiClient) SetConfigManager(mgr *Manager) {
 	c.configMgr = mgr
 }
 
+// SetRetryConfig replaces the client's retry policy for transient HTTP
+// failures (429/5xx and network errors). Must be called before the first
+// request, since it rebuilds the underlying transport.
+func (c *AiClient) SetRetryConfig(config RetryConfig) {
+	c.client.Transport = newRetryTransport(config)
+}
+
+// SetHistoryIndexer attaches a HistoryIndexer so TranslateNaturalLanguage can
+// retrieve similar past query/command pairs as few-shot examples.
+func (c *AiClient) SetHistoryIndexer(indexer *HistoryIndexer) {
+	c.history = indexer
+}
+
+// SetUsageTracker attaches a UsageTracker so GetResponseFromChatMessages
+// records every completion's token usage and cost to the usage log.
+func (c *AiClient) SetUsageTracker(tracker *UsageTracker) {
+	c.usage = tracker
+}
+
+// SetBackendConfigLoader attaches a BackendConfigLoader so determineAPIType
+// and the endpoint-selection code can pull provider and connection details
+// from user-defined YAML profiles instead of only the hard-coded providers.
+func (c *AiClient) SetBackendConfigLoader(loader *BackendConfigLoader) {
+	c.backends = loader
+}
+
 // determineAPIType determines which API to use based on the model and configuration
 func (c *AiClient) determineAPIType(model string) string {
+	// A YAML-defined backend profile matching the model name takes priority
+	// over both the config manager and the legacy configuration, since it's
+	// the most specific source of truth for that model.
+	if c.backends != nil {
+		if backend, exists := c.backends.GetByName(model); exists {
+			switch backend.Provider {
+			case "openai":
+				return "responses"
+			case "azure":
+				return "azure"
+			default:
+				return "openrouter"
+			}
+		}
+	}
+
 	// If we have a config manager, try to get the current model configuration
 	if c.configMgr != nil {
 		if modelConfig, exists := c.configMgr.GetCurrentModelConfig(); exists {
@@ -199,24 +302,14 @@ func (c *AiClient) GetResponseFromChatMessages(ctx context.Context, chatMessages
 	logger.Debug("Using API type: %s for model: %s", apiType, model)
 
 	// Route to appropriate API
-	var response string
+	var result CompletionResult
 	var err error
 
 	switch apiType {
 	case "responses":
-		response, err = c.Response(ctx, aiMessages, model)
-	case "azure":
-		response, err = c.ChatCompletion(ctx, aiMessages, model)
-	case "openrouter":
-		response, err = c.ChatCompletion(ctx, aiMessages, model)
-	case "requesty":
-		response, err = c.ChatCompletion(ctx, aiMessages, model)
-	case "zai":
-		response, err = c.ChatCompletion(ctx, aiMessages, model)
-	case "xai":
-		response, err = c.ChatCompletion(ctx, aiMessages, model)
-	case "alibaba":
-		response, err = c.ChatCompletion(ctx, aiMessages, model)
+		result, err = c.ResponseWithUsage(ctx, aiMessages, model)
+	case "azure", "openrouter", "requesty", "zai", "xai", "alibaba":
+		result, err = c.ChatCompletionWithUsage(ctx, aiMessages, model)
 	default:
 		return "", fmt.Errorf("unknown API type: %s", apiType)
 	}
@@ -225,7 +318,13 @@ func (c *AiClient) GetResponseFromChatMessages(ctx context.Context, chatMessages
 		return "", err
 	}
 
-	return response, nil
+	if c.usage != nil {
+		if recErr := c.usage.Record(apiType, model, result.Usage); recErr != nil {
+			logger.Debug("Failed to record usage for model %s: %v", model, recErr)
+		}
+	}
+
+	return result.Text, nil
 }
 
 // ChatCompletion sends a chat completion request to the OpenRouter API
@@ -243,8 +342,20 @@ func (c *AiClient) ChatCompletion(ctx context.Context, messages []Message, model
 	var apiVersion string
 	var deploymentName string
 
+	// A YAML-defined backend profile matching the model name takes priority
+	// over both the config manager and the legacy configuration, mirroring
+	// determineAPIType's precedence.
+	if c.backends != nil {
+		if backend, exists := c.backends.GetByName(model); exists {
+			provider = backend.Provider
+			apiKey = backend.APIKey()
+			baseURL = backend.BaseURL
+			backend.Parameters.ApplyToChatRequest(&reqBody)
+		}
+	}
+
 	// Try to get model configuration
-	if c.configMgr != nil {
+	if provider == "" && c.configMgr != nil {
 		if modelConfig, exists := c.configMgr.GetCurrentModelConfig(); exists {
 			provider = modelConfig.Provider
 			apiKey = modelConfig.APIKey
@@ -352,8 +463,9 @@ func (c *AiClient) ChatCompletion(ctx context.Context, messages []Message, model
 
 	// Check for errors
 	if resp.StatusCode != http.StatusOK {
-		logger.Error("API returned error: %s", body)
-		return "", fmt.Errorf("API returned error: %s", body)
+		apiErr := parseAPIError(resp.StatusCode, body)
+		logger.Error("API returned error: %v", apiErr)
+		return "", apiErr
 	}
 
 	// Parse the response
@@ -409,8 +521,18 @@ func (c *AiClient) Response(ctx context.Context, messages []Message, model strin
 	var apiKey string
 	var baseURL string
 
+	// A YAML-defined backend profile matching the model name takes priority,
+	// mirroring determineAPIType's precedence.
+	if c.backends != nil {
+		if backend, exists := c.backends.GetByName(model); exists && backend.Provider == "openai" {
+			apiKey = backend.APIKey()
+			baseURL = backend.BaseURL
+			backend.Parameters.ApplyToResponseRequest(&reqBody)
+		}
+	}
+
 	// Try to get model configuration
-	if c.configMgr != nil {
+	if apiKey == "" && c.configMgr != nil {
 		if modelConfig, exists := c.configMgr.GetCurrentModelConfig(); exists && modelConfig.Provider == "openai" {
 			apiKey = modelConfig.APIKey
 			baseURL = modelConfig.BaseURL
@@ -477,8 +599,9 @@ func (c *AiClient) Response(ctx context.Context, messages []Message, model strin
 
 	// Check for errors
 	if resp.StatusCode != http.StatusOK {
-		logger.Error("Responses API returned error: %s", body)
-		return "", fmt.Errorf("API returned error: %s", body)
+		apiErr := parseAPIError(resp.StatusCode, body)
+		logger.Error("Responses API returned error: %v", apiErr)
+		return "", apiErr
 	}
 
 	// Parse the response
@@ -564,14 +687,14 @@ func (c *AiClient) TranslateNaturalLanguage(naturalLanguage string, osName strin
 		shellPath = "/bin/bash"
 	}
 
-	systemPrompt := fmt.Sprintf(`You are a shell command translator. Convert natural language to shell commands.
+	systemPrompt := fmt.Sprintf(`%s
 
 Operating System: %s
 Shell: %s
 Current Directory: %s
 
 Rules:
-1. Output ONLY a single shell command, nothing else
+1. %s
 2. No explanations, no comments, no markdown
 3. Command should be safe and follow best practices
 
@@ -582,7 +705,19 @@ Output: ls -la
 Input: "find python files"
 Output: find . -name "*.py"
 
-Respond with ONLY the command.`, osName, shellPath, cwd)
+Respond with ONLY the command.`, i18n.Get("prompt.translate_single_system"), osName, shellPath, cwd, i18n.Get("prompt.translate_single_rules"))
+
+	// If a history indexer is attached, retrieve the most similar past
+	// query/command pairs and fold them in as additional few-shot examples
+	// so repeat workflows translate more reliably.
+	if c.history != nil {
+		examples, err := c.history.FewShotExamples(context.Background(), naturalLanguage, 3)
+		if err != nil {
+			logger.Debug("Failed to retrieve history few-shot examples: %v", err)
+		} else if len(examples) > 0 {
+			systemPrompt += "\n\nSimilar past translations:\n" + strings.Join(examples, "\n\n")
+		}
+	}
 
 	userPrompt := fmt.Sprintf("Translate: %s", naturalLanguage)
 
@@ -645,14 +780,27 @@ Respond with ONLY the command.`, osName, shellPath, cwd)
 	return response, nil
 }
 
-// TranslateNaturalLanguageMultiple generates multiple shell command options from natural language
-func (c *AiClient) TranslateNaturalLanguageMultiple(naturalLanguage string, osName string, shellPath string, cwd string, model string, count int) ([]string, error) {
+// TranslateNaturalLanguageMultiple generates multiple shell command options
+// from natural language, as CommandOptions so callers like SuggestCommands
+// get Explanation/Risk for free when the structured path succeeds.
+// Explanation and Risk are left empty when the heuristic prose parser had
+// to be used, since it has no way to recover either from freeform text.
+func (c *AiClient) TranslateNaturalLanguageMultiple(naturalLanguage string, osName string, shellPath string, cwd string, model string, count int) ([]CommandOption, error) {
+	// Prefer structured JSON-mode output; only fall back to the heuristic
+	// prose parser below when the provider doesn't honor response_format or
+	// the reply doesn't validate against the schema.
+	options, structuredErr := c.TranslateNaturalLanguageMultipleStructured(naturalLanguage, osName, shellPath, cwd, model, count)
+	if structuredErr == nil {
+		return options, nil
+	}
+	logger.Debug("Structured translation failed, falling back to heuristic parser: %v", structuredErr)
+
 	// Build AI prompt for command translation with multiple options
 	if shellPath == "" {
 		shellPath = "/bin/bash"
 	}
 
-	systemPrompt := `You are a Linux shell command generator. Output ONLY the most commonly used Linux shell commands.
+	systemPrompt := i18n.Get("prompt.shell_generator") + ` Output ONLY the most commonly used Linux shell commands.
 
 ABSOLUTE REQUIREMENTS:
 1. Output ONLY the 5 MOST COMMONLY USED Linux shell commands for the given task
@@ -741,143 +889,13 @@ Output ONLY the 5 most commonly used commands. If no valid commands, output NOTH
 		return nil, fmt.Errorf("AI API call failed: %v", err)
 	}
 
-	// Parse the response into multiple options
-	response = strings.TrimSpace(response)
-	lines := strings.Split(response, "\n")
-
-	// Regex to match leading numbers with various separators: "1. ", "1) ", "10. ", "10) ", "1)", "1.", etc.
-	// Match: digits followed by . or ) optionally followed by space
-	numberPrefixRegex := regexp.MustCompile(`^\s*\d+[.)]\s*`)
-
-	var options []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// Skip empty lines
-		if line == "" {
-			continue
-		}
-		// Remove numbering patterns using regex - be aggressive
-		line = numberPrefixRegex.ReplaceAllString(line, "")
-		// Also try to remove standalone numbers at start (like "1)" or "1.")
-		line = regexp.MustCompile(`^\d+[.)]\s*`).ReplaceAllString(line, "")
-		line = strings.TrimSpace(line)
-		
-		// If we removed everything, skip this line
-		if line == "" {
-			continue
-		}
-		// Remove any code block markers
-		line = strings.TrimPrefix(line, "```bash")
-		line = strings.TrimPrefix(line, "```sh")
-		line = strings.TrimPrefix(line, "```")
-		line = strings.TrimSuffix(line, "```")
-		line = strings.TrimSpace(line)
-
-		// STRICT FILTERING: Only accept lines that are valid Linux commands
-		// Reject anything that looks like text, explanations, or comments
-		
-		// Skip empty lines
-		if line == "" {
-			continue
-		}
-		
-		// Skip if it contains common explanatory phrases (anywhere in the line)
-		explanatoryPatterns := []string{
-			"Input:", "Output:", "Examples:", "Task:", "Rules:", "CRITICAL",
-			"I notice", "I'll", "Here are", "You can", "This will", "Note:",
-			"Warning:", "Error:", "Tip:", "Remember:", "provide", "accomplish",
-			"interpret", "appears", "typo", "will interpret", "as", "and provide",
-			"commands to", "display", "content", "information", "notice the",
-			"appears to have", "interpret this", "show me", "more", "less",
-		}
-		hasExplanatoryText := false
-		lowerLine := strings.ToLower(line)
-		for _, pattern := range explanatoryPatterns {
-			if strings.Contains(lowerLine, strings.ToLower(pattern)) {
-				hasExplanatoryText = true
-				break
-			}
-		}
-		
-		// Also skip lines that look like sentences (contain multiple common words that aren't command-related)
-		// Check for patterns like "I'll interpret this as:" or "Here are commands:"
-		if regexp.MustCompile(`(I|I'll|I will|Here|This|These|The|A|An)\s+(notice|will|can|are|is|was|were)`).MatchString(lowerLine) {
-			hasExplanatoryText = true
-		}
-		
-		// Skip lines that start with "I notice" or similar patterns
-		if regexp.MustCompile(`^I\s+(notice|will|can|interpret)`).MatchString(lowerLine) {
-			hasExplanatoryText = true
-		}
-		
-		// Skip lines containing "appears to have" or "interpret this as"
-		if regexp.MustCompile(`(appears to have|interpret this as|and provide commands)`).MatchString(lowerLine) {
-			hasExplanatoryText = true
-		}
-		
-		// Skip lines that contain colons followed by explanatory text (like "Task: show me")
-		if regexp.MustCompile(`:\s+[A-Z]`).MatchString(line) && len(line) > 50 {
-			hasExplanatoryText = true
-		}
-		
-		if hasExplanatoryText {
-			continue
-		}
-		
-		// Skip if it's just numbers/punctuation
-		if regexp.MustCompile(`^\d+[.)]?\s*$`).MatchString(line) {
-			continue
-		}
-		
-		// Skip if line is too long (commands are rarely > 150 chars)
-		if len(line) > 150 {
-			continue
-		}
-		
-		// STRICT: Must start with a valid command name (letters, no spaces before)
-		// Valid commands start with: letter, then may have letters/numbers/dashes/underscores
-		// Then may have space, options, arguments, pipes, redirects, etc.
-		commandPattern := regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*(\s|$|>|<|\||&|;)`)
-		if !commandPattern.MatchString(line) {
-			continue
-		}
-		
-		// Additional validation: must contain at least one space or be a single-word command
-		// and must not be just punctuation or special chars
-		if !regexp.MustCompile(`[a-zA-Z0-9]`).MatchString(line) {
-			continue
-		}
-		
-		// If we get here, it's likely a valid command
-		options = append(options, line)
-		
-		// Limit to maximum 5 commands
-		if len(options) >= 5 {
-			break
-		}
-	}
-
-	// Deduplicate options (case-insensitive, but preserve original case)
-	seen := make(map[string]bool)
-	var uniqueOptions []string
-	for _, opt := range options {
-		lower := strings.ToLower(strings.TrimSpace(opt))
-		if !seen[lower] {
-			seen[lower] = true
-			uniqueOptions = append(uniqueOptions, opt)
-			// Limit to maximum 5 unique commands
-			if len(uniqueOptions) >= 5 {
-				break
-			}
-		}
-	}
-	options = uniqueOptions
-
-	// If no valid commands found, return empty slice (not an error)
-	// The shell script will handle empty results gracefully
-	if len(options) == 0 {
-		return []string{}, nil
+	// Parse the response into multiple options. The heuristic extraction
+	// itself lives in ParseCommandLines so it can be exercised directly by
+	// the regression corpus in parse_test.go.
+	commands := ParseCommandLines(response)
+	options = make([]CommandOption, 0, len(commands))
+	for _, cmd := range commands {
+		options = append(options, CommandOption{Cmd: cmd})
 	}
-
 	return options, nil
 }
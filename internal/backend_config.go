@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/andreim2k/aiterm/config"
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// ModelParameters holds the per-call generation parameters a backend profile
+// may set. ApplyToChatRequest/ApplyToResponseRequest copy them onto the
+// outgoing request for the API the profile resolves to.
+type ModelParameters struct {
+	Temperature *float64 `yaml:"temperature,omitempty"`
+	TopP        *float64 `yaml:"top_p,omitempty"`
+	MaxTokens   *int     `yaml:"max_tokens,omitempty"`
+	Stop        []string `yaml:"stop,omitempty"`
+	Seed        *int     `yaml:"seed,omitempty"`
+}
+
+// ApplyToChatRequest copies the profile's generation parameters onto a
+// chat-completions request.
+func (p ModelParameters) ApplyToChatRequest(req *ChatCompletionRequest) {
+	req.Temperature = p.Temperature
+	req.TopP = p.TopP
+	req.MaxTokens = p.MaxTokens
+	req.Stop = p.Stop
+	req.Seed = p.Seed
+}
+
+// ApplyToResponseRequest copies the profile's generation parameters onto a
+// Responses API request. The Responses API has no stop/seed equivalent, so
+// those are dropped.
+func (p ModelParameters) ApplyToResponseRequest(req *ResponseRequest) {
+	req.Temperature = p.Temperature
+	req.TopP = p.TopP
+	req.MaxOutputTokens = p.MaxTokens
+}
+
+// ModelTemplate holds Go text/template snippets used to build prompts for a
+// backend profile.
+type ModelTemplate struct {
+	Chat       string `yaml:"chat,omitempty"`
+	Completion string `yaml:"completion,omitempty"`
+}
+
+// BackendConfig describes a single model profile loaded from
+// ~/.config/aiterm/models.d/*.yaml.
+type BackendConfig struct {
+	Name       string          `yaml:"name"`
+	Provider   string          `yaml:"provider"`
+	BaseURL    string          `yaml:"base_url,omitempty"`
+	APIKeyEnv  string          `yaml:"api_key_env,omitempty"`
+	Parameters ModelParameters `yaml:"parameters,omitempty"`
+	Template   ModelTemplate   `yaml:"template,omitempty"`
+	Grammar    string          `yaml:"grammar,omitempty"`
+}
+
+// APIKey resolves the profile's API key from its configured environment
+// variable.
+func (b *BackendConfig) APIKey() string {
+	if b.APIKeyEnv == "" {
+		return ""
+	}
+	return os.Getenv(b.APIKeyEnv)
+}
+
+// RenderChatPrompt executes the profile's chat template against the given
+// messages, for providers that expect a single rendered prompt string rather
+// than a structured messages array.
+func (b *BackendConfig) RenderChatPrompt(messages []Message) (string, error) {
+	if b.Template.Chat == "" {
+		return "", fmt.Errorf("backend %s has no chat template configured", b.Name)
+	}
+
+	tmpl, err := template.New(b.Name + "-chat").Parse(b.Template.Chat)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse chat template for %s: %w", b.Name, err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, map[string]interface{}{"Messages": messages}); err != nil {
+		return "", fmt.Errorf("failed to render chat template for %s: %w", b.Name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// BackendConfigLoader loads and caches BackendConfig profiles from a
+// directory of YAML files, letting users add new OpenAI-compatible
+// providers without recompiling.
+type BackendConfigLoader struct {
+	backends map[string]*BackendConfig
+}
+
+// NewBackendConfigLoader creates an empty BackendConfigLoader.
+func NewBackendConfigLoader() *BackendConfigLoader {
+	return &BackendConfigLoader{backends: make(map[string]*BackendConfig)}
+}
+
+// LoadAll reads every *.yaml file in dir and registers it as a backend
+// profile keyed by its `name` field (or the filename if unset). A missing
+// directory is not an error.
+func (l *BackendConfigLoader) LoadAll(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read backend config dir %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Error("Failed to read backend config %s: %v", path, err)
+			continue
+		}
+
+		var cfg BackendConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			logger.Error("Failed to parse backend config %s: %v", path, err)
+			continue
+		}
+
+		if cfg.Name == "" {
+			cfg.Name = strings.TrimSuffix(entry.Name(), ".yaml")
+		}
+
+		l.backends[cfg.Name] = &cfg
+		logger.Debug("Loaded backend config profile: %s (provider: %s)", cfg.Name, cfg.Provider)
+	}
+
+	return nil
+}
+
+// GetByName returns the backend profile with the given name, if loaded.
+func (l *BackendConfigLoader) GetByName(name string) (*BackendConfig, bool) {
+	cfg, exists := l.backends[name]
+	return cfg, exists
+}
+
+// Names returns the names of all loaded backend profiles.
+func (l *BackendConfigLoader) Names() []string {
+	names := make([]string, 0, len(l.backends))
+	for name := range l.backends {
+		names = append(names, name)
+	}
+	return names
+}
+
+// DefaultModelsDir returns ~/.config/aiterm/models.d, the default location
+// BackendConfigLoader.LoadAll scans for model profiles.
+func DefaultModelsDir() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve config dir: %w", err)
+	}
+	return filepath.Join(configDir, "models.d"), nil
+}
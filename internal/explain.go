@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// ExplainCommand returns a short, single-line description of cmd: the
+// man-page synopsis for its leading binary (via whatis), if one exists,
+// followed by an AI-generated gloss of what the full command line does. It
+// backs the --explain-cmd flag, used as the fzf preview command in
+// RunFzfSelect so a user can see what a candidate does before choosing it.
+func ExplainCommand(mgr *Manager, cmd string) (string, error) {
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" {
+		return "", nil
+	}
+
+	var parts []string
+	if synopsis := whatisSynopsis(cmd); synopsis != "" {
+		parts = append(parts, synopsis)
+	}
+
+	if gloss, err := aiGloss(mgr, cmd); err != nil {
+		logger.Debug("Failed to get AI gloss for %q: %v", cmd, err)
+	} else if gloss != "" {
+		parts = append(parts, gloss)
+	}
+
+	if len(parts) == 0 {
+		return cmd, nil
+	}
+	return strings.Join(parts, " — "), nil
+}
+
+// whatisSynopsis returns the first line of `whatis <binary>` for the
+// leading word of cmd, or "" if whatis is unavailable or knows nothing
+// about it.
+func whatisSynopsis(cmd string) string {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	out, err := exec.Command("whatis", fields[0]).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+}
+
+// aiGloss asks the model for a short, single-line explanation of what cmd
+// does, following the same chat-message plumbing as TranslateNaturalLanguage.
+func aiGloss(mgr *Manager, cmd string) (string, error) {
+	systemPrompt := `You explain shell commands in one short sentence.
+No markdown, no command repetition, no leading "This command" boilerplate.
+Respond with ONLY the explanation.`
+
+	messages := []ChatMessage{
+		{Content: systemPrompt, FromUser: false},
+		{Content: fmt.Sprintf("Explain: %s", cmd), FromUser: true},
+	}
+
+	ctx := context.Background()
+	response, err := mgr.AiClient.GetResponseFromChatMessages(ctx, messages, mgr.GetModel())
+	if err != nil {
+		return "", fmt.Errorf("failed to get AI explanation: %w", err)
+	}
+
+	return strings.TrimSpace(response), nil
+}
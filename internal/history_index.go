@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/andreim2k/aiterm/config"
+	"github.com/andreim2k/aiterm/logger"
+	"github.com/andreim2k/aiterm/vectorstore"
+)
+
+// DefaultEmbeddingModel is used to embed shell history when the caller
+// doesn't override it.
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+// HistoryIndexer embeds and retrieves prior shell commands and AI responses
+// for retrieval-augmented natural-language translation.
+type HistoryIndexer struct {
+	client *AiClient
+	store  *vectorstore.VectorStore
+	model  string
+}
+
+// NewHistoryIndexer opens the shell-history vector store under the config
+// dir and returns a HistoryIndexer backed by client for embedding calls.
+func NewHistoryIndexer(client *AiClient) (*HistoryIndexer, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config dir: %w", err)
+	}
+
+	store, err := vectorstore.Open(filepath.Join(configDir, "history.db"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history vector store: %w", err)
+	}
+
+	return &HistoryIndexer{client: client, store: store, model: DefaultEmbeddingModel}, nil
+}
+
+// Close releases the underlying vector store.
+func (h *HistoryIndexer) Close() error {
+	return h.store.Close()
+}
+
+// IndexEntry embeds a natural-language query paired with the shell command
+// it translated to (plus the AI's response text) and persists it for future
+// retrieval.
+func (h *HistoryIndexer) IndexEntry(ctx context.Context, id, query, command, response string) error {
+	vectors, err := h.client.Embeddings(ctx, []string{query}, h.model)
+	if err != nil {
+		return fmt.Errorf("failed to embed history entry: %w", err)
+	}
+	if len(vectors) == 0 {
+		return fmt.Errorf("no embedding returned for history entry")
+	}
+
+	return h.store.Upsert(vectorstore.Record{
+		ID:        id,
+		Text:      query,
+		Embedding: vectors[0],
+		Metadata: map[string]string{
+			"command":  command,
+			"response": response,
+		},
+	})
+}
+
+// FewShotExamples retrieves the top-K historical query/command pairs most
+// similar to query, formatted as few-shot examples ready to inject into a
+// translation prompt.
+func (h *HistoryIndexer) FewShotExamples(ctx context.Context, query string, k int) ([]string, error) {
+	vectors, err := h.client.Embeddings(ctx, []string{query}, h.model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("no embedding returned for query")
+	}
+
+	matches, err := h.store.TopK(vectors[0], k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search history: %w", err)
+	}
+
+	examples := make([]string, 0, len(matches))
+	for _, match := range matches {
+		command := match.Metadata["command"]
+		if command == "" {
+			continue
+		}
+		examples = append(examples, fmt.Sprintf("Input: %q\nOutput: %s", match.Text, command))
+	}
+
+	return examples, nil
+}
+
+// Reindex rebuilds the history vector store by re-embedding every shell
+// command recorded in mgr.ExecHistory, stored together with its captured
+// output. It is exposed for the `--reindex` CLI subcommand.
+func Reindex(ctx context.Context, mgr *Manager, indexer *HistoryIndexer) error {
+	for i, entry := range mgr.ExecHistory {
+		id := fmt.Sprintf("exec-%d", i)
+		if err := indexer.IndexEntry(ctx, id, entry.Command, entry.Command, entry.Output); err != nil {
+			logger.Error("Failed to index history entry %d: %v", i, err)
+			continue
+		}
+	}
+
+	logger.Info("Reindexed %d shell history entries", len(mgr.ExecHistory))
+	return nil
+}
@@ -0,0 +1,104 @@
+// Package i18n loads translatable message catalogs for aiterm's LLM prompts
+// and the heuristic command parser's explanatory-phrase blocklist, so
+// non-English model output is filtered as effectively as English output.
+package i18n
+
+import (
+	"embed"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+//go:embed locales
+var localesFS embed.FS
+
+// DefaultLocale is used when LANG/LANGUAGE don't name a catalog we ship.
+const DefaultLocale = "en"
+
+// explanatoryPhraseIDs lists the message IDs, in the order the heuristic
+// parser should check them, that make up the explanatory-phrase blocklist.
+var explanatoryPhraseIDs = []string{
+	"explain.i_notice",
+	"explain.i_will",
+	"explain.here_are",
+	"explain.you_can",
+	"explain.this_will",
+	"explain.note",
+	"explain.warning",
+	"explain.error",
+	"explain.tip",
+	"explain.remember",
+	"explain.appears_to_have",
+	"explain.interpret_this",
+	"explain.show_me",
+}
+
+var (
+	activeOnce sync.Once
+	active     *gotext.Po
+)
+
+// Locale detects the active locale from LANG/LANGUAGE, falling back to
+// DefaultLocale when neither is set or names a catalog we don't ship.
+func Locale() string {
+	for _, env := range []string{"LANGUAGE", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		// LANG is typically "fr_FR.UTF-8"; LANGUAGE may be "fr:en".
+		value = strings.SplitN(value, ":", 2)[0]
+		value = strings.SplitN(value, ".", 2)[0]
+		value = strings.SplitN(value, "_", 2)[0]
+		value = strings.ToLower(value)
+		if hasCatalog(value) {
+			return value
+		}
+	}
+	return DefaultLocale
+}
+
+func hasCatalog(locale string) bool {
+	_, err := localesFS.ReadFile("locales/" + locale + "/default.po")
+	return err == nil
+}
+
+// catalog loads (and caches) the active locale's .po catalog.
+func catalog() *gotext.Po {
+	activeOnce.Do(func() {
+		locale := Locale()
+		data, err := localesFS.ReadFile("locales/" + locale + "/default.po")
+		if err != nil {
+			// Fall back to English; it's embedded and always present.
+			data, _ = localesFS.ReadFile("locales/" + DefaultLocale + "/default.po")
+		}
+		po := gotext.NewPo()
+		po.Parse(data)
+		active = po
+	})
+	return active
+}
+
+// Get returns the active locale's translation for id, or id itself if no
+// translation is registered.
+func Get(id string) string {
+	translated := catalog().Get(id)
+	if translated == "" {
+		return id
+	}
+	return translated
+}
+
+// ExplanatoryPhrases returns the active locale's explanatory-phrase
+// blocklist, used by the heuristic command parser to filter LLM prose out
+// of extracted command suggestions.
+func ExplanatoryPhrases() []string {
+	phrases := make([]string, 0, len(explanatoryPhraseIDs))
+	for _, id := range explanatoryPhraseIDs {
+		phrases = append(phrases, Get(id))
+	}
+	return phrases
+}
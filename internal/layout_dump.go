@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/andreim2k/aiterm/system"
+	"github.com/andreim2k/aiterm/system/layout"
+)
+
+// RunDumpSession is the entry point for the `aiterm dump` subcommand. It
+// walks sessionTarget (the session to dump, or "" for the current one per
+// system.TmuxCurrentWindowTarget) and writes the equivalent layout.Config
+// to outPath as YAML, so a user can persist a session they built
+// interactively or share it to another machine.
+func RunDumpSession(sessionTarget, outPath string) error {
+	if sessionTarget == "" {
+		target, err := system.TmuxCurrentWindowTarget()
+		if err != nil {
+			return fmt.Errorf("failed to resolve current session: %w", err)
+		}
+		sessionTarget = target
+	}
+
+	cfg, err := layout.DumpSession(sessionTarget)
+	if err != nil {
+		return fmt.Errorf("failed to dump session %s: %w", sessionTarget, err)
+	}
+
+	if err := layout.Save(outPath, cfg); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -43,6 +43,7 @@ type Manager struct {
 	OS               string
 	SessionOverrides map[string]interface{} // session-only config overrides
 	LoadedKBs        map[string]string      // Loaded knowledge bases (name -> content)
+	Suggestions      *SuggestionCache       // ghost-text autosuggestion cache, keyed by (cwd, prefix, model)
 
 	// Functions for mocking
 	confirmedToExec   func(command string, prompt string, edit bool) (bool, string)
@@ -86,17 +87,32 @@ func NewManager(cfg *config.Config, shellMode bool) (*Manager, error) {
 		OS:               os,
 		SessionOverrides: make(map[string]interface{}),
 		LoadedKBs:        make(map[string]string),
+		Suggestions:      NewSuggestionCache(256),
 	}
 
 	// Set the config manager in the AI client
 	aiClient.SetConfigManager(manager)
 
+	if tracker, err := NewUsageTracker(); err != nil {
+		logger.Debug("Failed to set up usage tracking: %v", err)
+	} else {
+		aiClient.SetUsageTracker(tracker)
+	}
+
 	manager.confirmedToExec = manager.confirmedToExecFn
 	manager.getTmuxPanesInXml = manager.getTmuxPanesInXmlFn
 
 	// Set up tmux styling
 	_ = system.TmuxSetupStyling()
 
+	// Attach a long-lived control-mode connection so the hot-path pane
+	// helpers (capture, details, title, resize, split, clear, send-keys)
+	// can reuse it instead of forking a fresh tmux subprocess per call.
+	// Non-fatal: the helpers all fall back to exec when this fails.
+	if err := system.EnableTmuxController(paneId); err != nil {
+		logger.Debug("Failed to attach tmux control mode connection: %v", err)
+	}
+
 	// In shell mode, we don't create an exec pane
 	if !shellMode {
 		manager.InitExecPane()
@@ -132,11 +148,18 @@ func NewManagerForTranslation(cfg *config.Config) (*Manager, error) {
 		OS:               os,
 		SessionOverrides: make(map[string]interface{}),
 		LoadedKBs:        make(map[string]string),
+		Suggestions:      NewSuggestionCache(256),
 	}
 
 	// Set the config manager in the AI client
 	aiClient.SetConfigManager(manager)
 
+	if tracker, err := NewUsageTracker(); err != nil {
+		logger.Debug("Failed to set up usage tracking: %v", err)
+	} else {
+		aiClient.SetUsageTracker(tracker)
+	}
+
 	manager.confirmedToExec = manager.confirmedToExecFn
 	manager.getTmuxPanesInXml = manager.getTmuxPanesInXmlFn
 
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/andreim2k/aiterm/internal/i18n"
+)
+
+// numberPrefixRegex matches leading numbers with various separators: "1. ",
+// "1) ", "10. ", "10) ", "1)", "1.", etc.
+var numberPrefixRegex = regexp.MustCompile(`^\s*\d+[.)]\s*`)
+
+var (
+	sentenceOpenerRegex  = regexp.MustCompile(`(I|I'll|I will|Here|This|These|The|A|An)\s+(notice|will|can|are|is|was|were)`)
+	iNoticeRegex         = regexp.MustCompile(`^I\s+(notice|will|can|interpret)`)
+	appearsToHaveRegex   = regexp.MustCompile(`(appears to have|interpret this as|and provide commands)`)
+	colonExplanationRgx  = regexp.MustCompile(`:\s+[A-Z]`)
+	numberOnlyRegex      = regexp.MustCompile(`^\d+[.)]?\s*$`)
+	validCommandStartRgx = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*(\s|$|>|<|\||&|;)`)
+	hasAlphanumericRegex = regexp.MustCompile(`[a-zA-Z0-9]`)
+)
+
+// ParseCommandLines extracts up to 5 unique, executable-looking shell
+// commands from a freeform LLM response, stripping numbering, markdown code
+// fences, and prose lines that match the explanatory-phrase blocklist. It is
+// the fallback used by TranslateNaturalLanguageMultiple when the provider
+// doesn't honor structured JSON output, and is kept as a standalone pure
+// function so it can be exercised directly by the regression corpus in
+// parse_test.go.
+func ParseCommandLines(response string) []string {
+	response = strings.TrimSpace(response)
+	lines := strings.Split(response, "\n")
+
+	// Deliberately excludes short, generic substrings like "as", "more", and
+	// "less": Contains-matching them against the whole line also matches
+	// them as substrings of real command names ("bash", "base64",
+	// "password" all contain "as"; "less"/"more" are pagers in their own
+	// right), dropping legitimate commands as if they were explanatory
+	// prose.
+	explanatoryPatterns := append([]string{
+		"Input:", "Output:", "Examples:", "Task:", "Rules:", "CRITICAL",
+		"provide", "accomplish", "interpret", "appears", "typo",
+		"will interpret", "and provide", "commands to", "display",
+		"content", "information", "notice the",
+	}, i18n.ExplanatoryPhrases()...)
+
+	var options []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line = numberPrefixRegex.ReplaceAllString(line, "")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line = strings.TrimPrefix(line, "```bash")
+		line = strings.TrimPrefix(line, "```sh")
+		line = strings.TrimPrefix(line, "```")
+		line = strings.TrimSuffix(line, "```")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		lowerLine := strings.ToLower(line)
+		hasExplanatoryText := false
+		for _, pattern := range explanatoryPatterns {
+			if strings.Contains(lowerLine, strings.ToLower(pattern)) {
+				hasExplanatoryText = true
+				break
+			}
+		}
+		if sentenceOpenerRegex.MatchString(lowerLine) {
+			hasExplanatoryText = true
+		}
+		if iNoticeRegex.MatchString(lowerLine) {
+			hasExplanatoryText = true
+		}
+		if appearsToHaveRegex.MatchString(lowerLine) {
+			hasExplanatoryText = true
+		}
+		if colonExplanationRgx.MatchString(line) && len(line) > 50 {
+			hasExplanatoryText = true
+		}
+		if hasExplanatoryText {
+			continue
+		}
+
+		if numberOnlyRegex.MatchString(line) {
+			continue
+		}
+		if len(line) > 150 {
+			continue
+		}
+		if !validCommandStartRgx.MatchString(line) {
+			continue
+		}
+		if !hasAlphanumericRegex.MatchString(line) {
+			continue
+		}
+
+		options = append(options, line)
+		if len(options) >= 5 {
+			break
+		}
+	}
+
+	seen := make(map[string]bool)
+	var uniqueOptions []string
+	for _, opt := range options {
+		lower := strings.ToLower(strings.TrimSpace(opt))
+		if !seen[lower] {
+			seen[lower] = true
+			uniqueOptions = append(uniqueOptions, opt)
+			if len(uniqueOptions) >= 5 {
+				break
+			}
+		}
+	}
+
+	if len(uniqueOptions) == 0 {
+		return []string{}
+	}
+	return uniqueOptions
+}
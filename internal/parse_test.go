@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// corpusCase is one stanza of testdata/parse_corpus.txt.
+type corpusCase struct {
+	name   string
+	input  string
+	expect []string
+}
+
+// loadParseCorpus parses the RE2-exec_test.go-style stanza file: blocks
+// separated by a line containing only "@@@", each holding a name line, an
+// "--- input ---" section, and an "--- expect ---" section.
+func loadParseCorpus(t *testing.T, path string) []corpusCase {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read corpus %s: %v", path, err)
+	}
+
+	var blocks [][]string
+	var current []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	started := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !started {
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			started = true
+		}
+		if line == "@@@" {
+			blocks = append(blocks, current)
+			current = nil
+			continue
+		}
+		current = append(current, line)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("failed to scan corpus %s: %v", path, err)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+
+	cases := make([]corpusCase, 0, len(blocks))
+	for _, block := range blocks {
+		if len(block) == 0 {
+			continue
+		}
+		name := block[0]
+		rest := block[1:]
+
+		inputStart := indexOf(rest, "--- input ---")
+		expectStart := indexOf(rest, "--- expect ---")
+		if inputStart == -1 || expectStart == -1 || expectStart < inputStart {
+			t.Fatalf("corpus case %q missing --- input --- / --- expect --- markers", name)
+		}
+
+		inputLines := rest[inputStart+1 : expectStart]
+		expectLines := rest[expectStart+1:]
+
+		cases = append(cases, corpusCase{
+			name:   name,
+			input:  strings.Join(inputLines, "\n"),
+			expect: expectLines,
+		})
+	}
+	return cases
+}
+
+func indexOf(lines []string, marker string) int {
+	for i, line := range lines {
+		if line == marker {
+			return i
+		}
+	}
+	return -1
+}
+
+// TestParseCommandLinesCorpus runs ParseCommandLines against the captured
+// real-world LLM output corpus in testdata/parse_corpus.txt. Any change to
+// the heuristic parser must show its diff against this corpus.
+func TestParseCommandLinesCorpus(t *testing.T) {
+	cases := loadParseCorpus(t, "testdata/parse_corpus.txt")
+	if len(cases) == 0 {
+		t.Fatal("corpus is empty")
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ParseCommandLines(tc.input)
+			want := tc.expect
+			if len(got) == 0 && len(want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseCommandLines(%q) = %#v, want %#v", tc.input, got, want)
+			}
+		})
+	}
+}
+
+// FuzzParseCommands guarantees the heuristic parser never panics or hangs
+// on adversarial byte sequences, regardless of whether they resemble LLM
+// output at all.
+func FuzzParseCommands(f *testing.F) {
+	f.Add("ls -la")
+	f.Add("1. ls -la\n2. find . -name \"*.py\"")
+	f.Add("```bash\nls -la\n```")
+	f.Add("")
+	f.Add(strings.Repeat("a", 10000))
+	f.Add("I notice you want to \x00\xff list files\nls -la")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		_ = ParseCommandLines(input)
+	})
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/andreim2k/aiterm/config"
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// PickerFrontend selects which UI draws the multi-option menu behind the
+// --ai-select flag.
+type PickerFrontend string
+
+const (
+	// PickerAuto uses fzf when it's on $PATH and falls back to the inline
+	// /dev/tty menu otherwise. This is the default.
+	PickerAuto PickerFrontend = "auto"
+	// PickerInline always uses the built-in menu from RunAISelect.
+	PickerInline PickerFrontend = "inline"
+	// PickerFzf always shells out to fzf, erroring if it isn't installed.
+	PickerFzf PickerFrontend = "fzf"
+)
+
+// RunPicker is the entry point for the --ai-select flag. It chooses a
+// frontend according to cfg.PickerFrontend (defaulting to PickerAuto when
+// unset) and renders options through it, returning whichever one the user
+// picked. An empty result with a nil error means the user cancelled.
+func RunPicker(cfg *config.Config, options []string) (string, error) {
+	frontend := PickerFrontend(cfg.PickerFrontend)
+	if frontend == "" {
+		frontend = PickerAuto
+	}
+
+	switch frontend {
+	case PickerFzf:
+		return RunFzfSelect(options)
+	case PickerInline:
+		return RunAISelect(options)
+	default:
+		if fzfPath, err := exec.LookPath("fzf"); err == nil {
+			logger.Debug("Using fzf picker frontend at %s", fzfPath)
+			return RunFzfSelect(options)
+		}
+		logger.Debug("fzf not found on $PATH, falling back to the inline picker")
+		return RunAISelect(options)
+	}
+}
+
+// RunFzfSelect pipes options into fzf with a live preview pane that shells
+// back into `aiterm --explain-cmd` for whatever candidate is highlighted, so
+// a user can see what each one does before committing to it. A single
+// option is returned immediately, same as RunAISelect. Aborting fzf
+// (Esc/Ctrl-C) returns an empty string and a nil error, matching
+// RunAISelect's cancellation behavior.
+func RunFzfSelect(options []string) (string, error) {
+	if len(options) == 0 {
+		return "", fmt.Errorf("no options to select from")
+	}
+	if len(options) == 1 {
+		return options[0], nil
+	}
+
+	if _, err := exec.LookPath("fzf"); err != nil {
+		return "", fmt.Errorf("fzf not found on $PATH: %w", err)
+	}
+
+	aitermPath := aitermExecutablePath()
+	cmd := exec.Command("fzf",
+		"--height=40%",
+		"--reverse",
+		"--prompt=translate> ",
+		"--preview="+aitermPath+" --explain-cmd {}",
+		"--preview-window=down:3:wrap",
+	)
+	cmd.Stdin = strings.NewReader(strings.Join(options, "\n"))
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 130 {
+			return "", nil
+		}
+		return "", fmt.Errorf("fzf selection failed: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// RetryConfig controls how the retry-aware http.RoundTripper installed by
+// NewAiClient handles transient failures.
+type RetryConfig struct {
+	MaxRetries        int
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	RetryableStatuses []int
+}
+
+// DefaultRetryConfig returns the retry policy used by NewAiClient unless the
+// caller overrides it via AiClient.SetRetryConfig.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:        4,
+		BaseDelay:         500 * time.Millisecond,
+		MaxDelay:          30 * time.Second,
+		RetryableStatuses: []int{http.StatusTooManyRequests, 500, 502, 503, 504},
+	}
+}
+
+// APIError is a typed error parsed from an OpenAI/Anthropic-style JSON error
+// body (`{"error":{"type":"rate_limit_error",...}}`), so callers such as the
+// TUI can render rate-limit and auth failures specially instead of matching
+// on error strings.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("API error (status %d, type %s): %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// parseAPIError extracts a typed APIError from a non-200 response body,
+// falling back to the raw body text if it isn't a recognized error envelope.
+func parseAPIError(statusCode int, body []byte) error {
+	var wrapper struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &wrapper); err == nil && wrapper.Error.Message != "" {
+		return &APIError{StatusCode: statusCode, Type: wrapper.Error.Type, Message: wrapper.Error.Message}
+	}
+
+	return &APIError{StatusCode: statusCode, Message: string(body)}
+}
+
+// retryTransport is an http.RoundTripper that retries on retryable statuses
+// and network errors, honoring Retry-After and backing off exponentially
+// with jitter.
+type retryTransport struct {
+	base   http.RoundTripper
+	config RetryConfig
+}
+
+func newRetryTransport(config RetryConfig) *retryTransport {
+	return &retryTransport{base: http.DefaultTransport, config: config}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer request body for retry: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= t.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err == nil && !isRetryableStatus(resp.StatusCode, t.config.RetryableStatuses) {
+			return resp, nil
+		}
+
+		if attempt == t.config.MaxRetries {
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+
+		delay := t.config.BaseDelay
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			} else {
+				delay = backoffDelay(t.config, attempt)
+			}
+		} else {
+			delay = backoffDelay(t.config, attempt)
+			lastErr = err
+		}
+
+		if resp != nil {
+			logger.Warn("Retrying request to %s after status %d (attempt %d/%d) in %s", req.URL, resp.StatusCode, attempt+1, t.config.MaxRetries, delay)
+			_ = resp.Body.Close()
+		} else {
+			logger.Warn("Retrying request to %s after error %v (attempt %d/%d) in %s", req.URL, lastErr, attempt+1, t.config.MaxRetries, delay)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return nil, lastErr
+}
+
+func isRetryableStatus(status int, retryable []int) bool {
+	for _, s := range retryable {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// backoffDelay computes min(cap, base*2^attempt) + rand(0..base).
+func backoffDelay(config RetryConfig, attempt int) time.Duration {
+	delay := config.BaseDelay * time.Duration(1<<uint(attempt))
+	if delay > config.MaxDelay {
+		delay = config.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(config.BaseDelay) + 1))
+	return delay + jitter
+}
+
+// parseRetryAfter parses a Retry-After header in either delta-seconds or
+// HTTP-date form.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,211 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/andreim2k/aiterm/config"
+)
+
+// RiskLevel classifies the blast radius of a shell command, independent of
+// whatever risk the model itself may have self-reported in a Suggestion.
+type RiskLevel string
+
+const (
+	RiskSafe        RiskLevel = "safe"
+	RiskModerate    RiskLevel = "moderate"
+	RiskDestructive RiskLevel = "destructive"
+)
+
+// RiskRule matches a command against a regex and assigns it a RiskLevel.
+// Rules are evaluated in order; the first match wins.
+type RiskRule struct {
+	Pattern     string    `yaml:"pattern"`
+	Level       RiskLevel `yaml:"level"`
+	Description string    `yaml:"description,omitempty"`
+
+	compiled *regexp.Regexp
+}
+
+// RiskPolicy is an ordered rules table plus the unmatched-command default.
+type RiskPolicy struct {
+	Rules   []RiskRule `yaml:"rules"`
+	Default RiskLevel  `yaml:"default"`
+}
+
+// DefaultRiskRules are the built-in rules table, covering the common ways a
+// generated command can do irreversible damage. A user-supplied policy file
+// (see LoadRiskPolicy) replaces this table entirely rather than merging
+// with it, so an org can ship a strictly narrower or broader rules set.
+func DefaultRiskRules() []RiskRule {
+	return []RiskRule{
+		{Pattern: `(?i)\brm\s+(-[a-zA-Z]*r[a-zA-Z]*f[a-zA-Z]*\b|-[a-zA-Z]*f[a-zA-Z]*r[a-zA-Z]*\b|-[a-zA-Z]*r[a-zA-Z]*\s+-[a-zA-Z]*f[a-zA-Z]*\b|-[a-zA-Z]*f[a-zA-Z]*\s+-[a-zA-Z]*r[a-zA-Z]*\b|--recursive.*--force|--force.*--recursive)`, Level: RiskDestructive, Description: "recursive force delete"},
+		{Pattern: `\bdd\s+.*\bof=`, Level: RiskDestructive, Description: "raw disk write"},
+		{Pattern: `\bmkfs(\.[a-zA-Z0-9]+)?\b`, Level: RiskDestructive, Description: "filesystem format"},
+		{Pattern: `\bcurl\b.*\|\s*(sudo\s+)?(ba)?sh\b`, Level: RiskDestructive, Description: "pipe remote script to shell"},
+		{Pattern: `\bwget\b.*\|\s*(sudo\s+)?(ba)?sh\b`, Level: RiskDestructive, Description: "pipe remote script to shell"},
+		{Pattern: `\bchmod\s+(-R\s+)?0?777\b`, Level: RiskDestructive, Description: "world-writable permissions"},
+		{Pattern: `\bgit\s+push\s+.*--force\b|\bgit\s+push\s+.*-f\b`, Level: RiskDestructive, Description: "force push rewrites remote history"},
+		{Pattern: `>\s*/(etc|boot|sys|dev|usr|bin|sbin)/`, Level: RiskDestructive, Description: "redirect into a system path"},
+		{Pattern: `\b(apt|apt-get|yum|dnf|pacman)\b.*\b(remove|purge|-R)\b`, Level: RiskModerate, Description: "package removal"},
+		{Pattern: `\bsudo\b`, Level: RiskModerate, Description: "elevated privileges"},
+		{Pattern: `\brm\b`, Level: RiskModerate, Description: "delete"},
+		{Pattern: `>\s*[^&|]`, Level: RiskModerate, Description: "redirection may overwrite an existing file"},
+	}
+}
+
+// DefaultRiskPolicy returns the built-in policy, used when no user policy
+// file is present.
+func DefaultRiskPolicy() *RiskPolicy {
+	return &RiskPolicy{Rules: DefaultRiskRules(), Default: RiskSafe}
+}
+
+// DefaultRiskPolicyPath returns ~/.config/aiterm/risk_policy.yaml, the path
+// a user or sysadmin can populate to override the built-in rules table.
+func DefaultRiskPolicyPath() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve config dir: %w", err)
+	}
+	return filepath.Join(configDir, "risk_policy.yaml"), nil
+}
+
+// LoadRiskPolicy reads a YAML risk policy from path. A missing file is not
+// an error; it just means DefaultRiskPolicy should be used instead.
+func LoadRiskPolicy(path string) (*RiskPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read risk policy %s: %w", path, err)
+	}
+
+	var policy RiskPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse risk policy %s: %w", path, err)
+	}
+	if policy.Default == "" {
+		policy.Default = RiskSafe
+	}
+
+	for i := range policy.Rules {
+		compiled, err := regexp.Compile(policy.Rules[i].Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("risk policy %s: invalid pattern %q: %w", path, policy.Rules[i].Pattern, err)
+		}
+		policy.Rules[i].compiled = compiled
+	}
+
+	return &policy, nil
+}
+
+// LoadUserRiskPolicy loads the policy at DefaultRiskPolicyPath, falling back
+// to DefaultRiskPolicy if no such file exists.
+func LoadUserRiskPolicy() (*RiskPolicy, error) {
+	path, err := DefaultRiskPolicyPath()
+	if err != nil {
+		return DefaultRiskPolicy(), nil
+	}
+
+	policy, err := LoadRiskPolicy(path)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = DefaultRiskPolicy()
+	}
+	return policy, nil
+}
+
+// Classify returns the RiskLevel of cmd under p, or p.Default if no rule
+// matches. Rules are evaluated in order and the first match wins.
+func (p *RiskPolicy) Classify(cmd string) RiskLevel {
+	for _, rule := range p.Rules {
+		pattern := rule.compiled
+		if pattern == nil {
+			pattern = regexp.MustCompile(rule.Pattern)
+		}
+		if pattern.MatchString(cmd) {
+			return rule.Level
+		}
+	}
+	if p.Default == "" {
+		return RiskSafe
+	}
+	return p.Default
+}
+
+// ClassifySuggestions sets ClassifiedRisk on each suggestion according to
+// policy, returning the same slice for convenient chaining.
+func ClassifySuggestions(policy *RiskPolicy, suggestions []Suggestion) []Suggestion {
+	for i := range suggestions {
+		suggestions[i].ClassifiedRisk = policy.Classify(suggestions[i].Cmd)
+	}
+	return suggestions
+}
+
+// PolicyMode governs what happens when a suggestion at or above a threshold
+// RiskLevel is about to be offered or executed.
+type PolicyMode string
+
+const (
+	// PolicyModeNone takes no special action regardless of risk level.
+	PolicyModeNone PolicyMode = ""
+	// PolicyModeConfirm requires an explicit y/N confirmation for commands
+	// at or above the threshold. Corresponds to --confirm=<level>.
+	PolicyModeConfirm PolicyMode = "confirm"
+	// PolicyModeDeny refuses to execute commands at or above the threshold
+	// outright, without prompting. Corresponds to --deny=<level>.
+	PolicyModeDeny PolicyMode = "deny"
+)
+
+var riskOrder = map[RiskLevel]int{
+	RiskSafe:        0,
+	RiskModerate:    1,
+	RiskDestructive: 2,
+}
+
+// EnforcePolicy decides what to do with a suggestion under the given mode
+// and threshold level (e.g. PolicyModeConfirm + RiskDestructive for
+// --confirm=destructive). It returns allow=false when PolicyModeDeny should
+// refuse execution outright, and confirm=true when the caller must prompt
+// the user before proceeding. It is the entry point for the
+// --confirm=<level>/--deny=<level> CLI flags.
+func EnforcePolicy(mode PolicyMode, threshold RiskLevel, suggestion Suggestion) (allow bool, confirm bool) {
+	if mode == PolicyModeNone || suggestion.ClassifiedRisk == "" {
+		return true, false
+	}
+	if riskOrder[suggestion.ClassifiedRisk] < riskOrder[threshold] {
+		return true, false
+	}
+	if mode == PolicyModeDeny {
+		return false, false
+	}
+	return true, true
+}
+
+// liveTranslateConfirmThreshold is the RiskLevel at/above which candidates
+// from the always-on --ai-translate-multiple pipeline (see
+// AnnotateConfirmPrefix) get the "[confirm] " treatment, rather than every
+// moderate-risk command (e.g. a plain sudo) nagging on every translation.
+const liveTranslateConfirmThreshold = RiskDestructive
+
+// AnnotateConfirmPrefix classifies cmd against policy and, if EnforcePolicy
+// says it needs confirmation at liveTranslateConfirmThreshold, prefixes it
+// with "[confirm] " - the same convention RunSuggestCommands uses for
+// --confirm=<level> - so the zsh/bash/fish wrappers can require an explicit
+// y/N before accepting a destructive translated command into the buffer.
+// This is what wires RiskPolicy into the live shell-wrapper pipeline rather
+// than only the standalone --ai-suggest-commands flag.
+func AnnotateConfirmPrefix(policy *RiskPolicy, cmd string) string {
+	suggestion := Suggestion{Cmd: cmd, ClassifiedRisk: policy.Classify(cmd)}
+	if _, confirm := EnforcePolicy(PolicyModeConfirm, liveTranslateConfirmThreshold, suggestion); confirm {
+		return "[confirm] " + cmd
+	}
+	return cmd
+}
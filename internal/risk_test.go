@@ -0,0 +1,26 @@
+package internal
+
+import "testing"
+
+func TestClassifyRecursiveForceDelete(t *testing.T) {
+	policy := DefaultRiskPolicy()
+
+	cases := map[string]RiskLevel{
+		"rm -rf foo":                 RiskDestructive,
+		"rm -Rf foo":                 RiskDestructive,
+		"rm -fR foo":                 RiskDestructive,
+		"rm -r -f foo":               RiskDestructive,
+		"rm -f -r foo":               RiskDestructive,
+		"rm --recursive --force foo": RiskDestructive,
+		"rm --force --recursive foo": RiskDestructive,
+		"rm foo":                     RiskModerate,
+		"rm -r foo":                  RiskModerate,
+		"rm -f foo":                  RiskModerate,
+	}
+
+	for cmd, want := range cases {
+		if got := policy.Classify(cmd); got != want {
+			t.Errorf("Classify(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ANSI colors used by the /dev/tty selector menu, matching the palette the
+// zsh here-doc used to draw inline.
+const (
+	selectorSelectedColor    = "\033[1;32m"
+	selectorNormalColor      = "\033[0m"
+	selectorInstructionColor = "\033[0;36m"
+)
+
+// RunAISelect renders an arrow-key-driven picker for options on /dev/tty and
+// returns the one the user chose. It is the built-in "inline" frontend
+// behind the `--ai-select` flag, used by RunPicker when fzf isn't available
+// or PickerInline is configured explicitly, so the zsh/bash/fish wrappers
+// never have to embed their own menu-drawing logic.
+//
+// A single option is returned immediately without drawing a menu, since
+// there is nothing to choose between. Cancelling (Esc, or "c"/"C") returns
+// an empty string and a nil error so callers can tell "user cancelled"
+// apart from a failure to read the terminal.
+func RunAISelect(options []string) (string, error) {
+	if len(options) == 0 {
+		return "", fmt.Errorf("no options to select from")
+	}
+	if len(options) == 1 {
+		return options[0], nil
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to open /dev/tty: %w", err)
+	}
+	defer tty.Close()
+
+	restore, err := makeRawTTY(tty)
+	if err != nil {
+		return "", fmt.Errorf("failed to set raw mode on /dev/tty: %w", err)
+	}
+	defer restore()
+
+	selected := 0
+	drawSelectorMenu(tty, options, selected)
+
+	reader := bufio.NewReader(tty)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			clearSelectorMenu(tty, len(options))
+			return "", fmt.Errorf("failed to read key from /dev/tty: %w", err)
+		}
+
+		switch b {
+		case '\r', '\n':
+			clearSelectorMenu(tty, len(options))
+			return options[selected], nil
+		case 'c', 'C':
+			clearSelectorMenu(tty, len(options))
+			return "", nil
+		case 0x1b: // Esc, possibly the start of an arrow-key escape sequence
+			next, err := reader.Peek(1)
+			if err != nil || next[0] != '[' {
+				clearSelectorMenu(tty, len(options))
+				return "", nil
+			}
+			_, _ = reader.Discard(1)
+			arrow, err := reader.ReadByte()
+			if err != nil {
+				continue
+			}
+			switch arrow {
+			case 'A': // Up
+				if selected > 0 {
+					selected--
+					drawSelectorMenu(tty, options, selected)
+				}
+			case 'B': // Down
+				if selected < len(options)-1 {
+					selected++
+					drawSelectorMenu(tty, options, selected)
+				}
+			}
+		}
+	}
+}
+
+// drawSelectorMenu redraws the full menu in place: every option on its own
+// line (the selected one highlighted) followed by a one-line legend.
+func drawSelectorMenu(tty *os.File, options []string, selected int) {
+	var b strings.Builder
+	b.WriteString("\r\033[J")
+	for i, opt := range options {
+		if i == selected {
+			fmt.Fprintf(&b, "%s➤ %s%s\r\n", selectorSelectedColor, opt, selectorNormalColor)
+		} else {
+			fmt.Fprintf(&b, "  %s\r\n", opt)
+		}
+	}
+	fmt.Fprintf(&b, "%s↑/↓: Navigate  Enter: Select  Esc/C: Cancel%s", selectorInstructionColor, selectorNormalColor)
+	_, _ = tty.WriteString(b.String())
+	// Move back up to the first option line so the next redraw overwrites cleanly.
+	fmt.Fprintf(tty, "\033[%dA\r", len(options))
+}
+
+// clearSelectorMenu wipes the menu (options + legend line) before returning
+// control to the shell. drawSelectorMenu leaves the cursor on the first
+// option line, so clearing to the end of the screen from there removes
+// every menu line.
+func clearSelectorMenu(tty *os.File, numOptions int) {
+	_, _ = tty.WriteString("\r\033[J")
+}
+
+// makeRawTTY puts tty into raw, no-echo mode so single keystrokes (including
+// arrow-key escape sequences) can be read without waiting for Enter, and
+// returns a func that restores the previous settings.
+func makeRawTTY(tty *os.File) (func(), error) {
+	saved, err := exec.Command("stty", "-F", tty.Name(), "-g").Output()
+	if err != nil {
+		saved, err = exec.Command("stty", "-f", tty.Name(), "-g").Output()
+		if err != nil {
+			return nil, err
+		}
+	}
+	savedState := strings.TrimSpace(string(saved))
+
+	if err := exec.Command("stty", "-F", tty.Name(), "raw", "-echo").Run(); err != nil {
+		if err := exec.Command("stty", "-f", tty.Name(), "raw", "-echo").Run(); err != nil {
+			return nil, err
+		}
+	}
+
+	return func() {
+		if err := exec.Command("stty", "-F", tty.Name(), savedState).Run(); err != nil {
+			_ = exec.Command("stty", "-f", tty.Name(), savedState).Run()
+		}
+	}, nil
+}
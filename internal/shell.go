@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/andreim2k/aiterm/internal/i18n"
 	"github.com/andreim2k/aiterm/logger"
 )
 
@@ -42,13 +43,27 @@ func (s *ShellInterface) Start() error {
 
 	fmt.Println("AI Shell Mode (aish) - Press Ctrl+X Ctrl+A to translate natural language to commands")
 	fmt.Println("Alternative bindings: Ctrl+Space or Alt+Space")
-	fmt.Println("This is your real zsh with AI superpowers!")
+
+	// Run the actual shell with our wrapper, pointed at the rc file that
+	// loads it. Each shell has its own mechanism for picking up a scoped rc:
+	// zsh via ZDOTDIR, fish via XDG_CONFIG_HOME, bash via --rcfile.
+	var cmd *exec.Cmd
+	switch {
+	case strings.Contains(s.shell, "zsh"):
+		fmt.Println("This is your real zsh with AI superpowers!")
+		cmd = exec.Command("zsh", "-i")
+		cmd.Env = append(os.Environ(), fmt.Sprintf("ZDOTDIR=%s", wrapperDir))
+	case strings.Contains(s.shell, "fish"):
+		fmt.Println("This is your real fish with AI superpowers!")
+		cmd = exec.Command("fish", "-i")
+		cmd.Env = append(os.Environ(), fmt.Sprintf("XDG_CONFIG_HOME=%s", wrapperDir))
+	default:
+		fmt.Println("This is your real bash with AI superpowers!")
+		cmd = exec.Command("bash", "--rcfile", filepath.Join(wrapperDir, ".bashrc"), "-i")
+		cmd.Env = os.Environ()
+	}
 	fmt.Println()
 
-	// Run the actual shell with our wrapper using ZDOTDIR
-	// Ensure shell is interactive so .zshrc loads
-	cmd := exec.Command("zsh", "-i")
-	cmd.Env = append(os.Environ(), fmt.Sprintf("ZDOTDIR=%s", wrapperDir))
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -65,29 +80,170 @@ func (s *ShellInterface) Start() error {
 
 // createShellWrapperScript creates a script that adds AI translation keybinding to the shell
 func (s *ShellInterface) createShellWrapperScript() (string, error) {
+	switch {
+	case strings.Contains(s.shell, "zsh"):
+		return s.createZshWrapperScript()
+	case strings.Contains(s.shell, "fish"):
+		return s.createFishWrapperScript()
+	case strings.Contains(s.shell, "bash"):
+		return s.createBashWrapperScript()
+	default:
+		return "", fmt.Errorf("shell wrapper not implemented for %s yet", s.shell)
+	}
+}
+
+// aitermExecutablePath returns an absolute, shell-quoted path to the running
+// aiterm binary, for use inside the generated wrapper scripts.
+func aitermExecutablePath() string {
+	aitermPath, err := filepath.Abs(os.Args[0])
+	if err != nil {
+		aitermPath = os.Args[0]
+	}
+	return fmt.Sprintf("\"%s\"", aitermPath)
+}
+
+// autosuggestBlock returns the zsh snippet that wires up ghost-text AI
+// autosuggestions, or "" when aish.autosuggest is off in config. It is
+// appended to the zsh wrapper content so the feature can be toggled
+// without the shell script having to ask aiterm about config at runtime.
+func (s *ShellInterface) autosuggestBlock(aitermPath string) string {
+	if s.manager == nil || !s.manager.Config.Aish.Autosuggest {
+		return ""
+	}
+	return fmt.Sprintf(zshAutosuggestTemplate, aitermPath)
+}
+
+// zshAutosuggestTemplate is, inspired by zsh-autosuggestions, an always-on
+// inline completion layer: every keystroke schedules a debounced
+// `--ai-suggest` request in the background (via zle -F so the result is
+// applied asynchronously without blocking typing) and the result is shown
+// dim/gray after the cursor using ZLE's POSTDISPLAY. The debounce sleeps out
+// a short window and drops the request if a later keystroke has since
+// superseded it, so a burst of typing forks at most one AI request, after
+// the user pauses. Right-Arrow/^F accepts the whole suggestion; Alt-F
+// accepts just its next word - both only when the cursor sits at the end of
+// the buffer, so normal cursor movement is untouched.
+const zshAutosuggestTemplate = `# --- AI ghost-text autosuggestions (aish.autosuggest) ---
+typeset -g _aiterm_suggestion=""
+typeset -g _aiterm_suggest_for_buffer=""
+typeset -g _aiterm_suggest_outfile=""
+typeset -g _aiterm_suggest_gen=0
+typeset -g _aiterm_suggest_genfile=$(mktemp)
+echo 0 > "$_aiterm_suggest_genfile"
+
+_aiterm_clear_suggestion() {
+	POSTDISPLAY=""
+	_aiterm_suggestion=""
+}
+
+# Runs in a forked subshell. Sleeps out the debounce window first, then only
+# actually spawns the AI request if no later keystroke has scheduled a newer
+# generation in the meantime - so a burst of typing forks at most one
+# "--ai-suggest" process, after the user pauses, instead of one per keystroke.
+_aiterm_fetch_suggestion() {
+	local buffer="$1" outfile="$2" gen="$3"
+	sleep 0.3
+	[ "$(cat "$_aiterm_suggest_genfile" 2>/dev/null)" = "$gen" ] || return 0
+	%s --ai-suggest "$buffer" > "$outfile" 2>/dev/null
+}
+
+_aiterm_suggestion_ready() {
+	local fd=$1
+	zle -F "$fd"
+	exec {fd}<&-
+
+	if [ "$BUFFER" = "$_aiterm_suggest_for_buffer" ] && [ -s "$_aiterm_suggest_outfile" ]; then
+		_aiterm_suggestion=$(cat "$_aiterm_suggest_outfile")
+		POSTDISPLAY="$_aiterm_suggestion"
+	fi
+	rm -f "$_aiterm_suggest_outfile"
+	zle -R
+}
+
+# Debounced trigger: bump the shared generation counter, then fork a
+# background request that sleeps out the debounce window before checking
+# whether it's still the latest generation (see _aiterm_fetch_suggestion).
+# Superseded requests skip the actual "--ai-suggest" call entirely, so a
+# fast typist never spawns more than one AI request per pause in typing.
+_aiterm_schedule_suggestion() {
+	_aiterm_clear_suggestion
+	[ -z "$BUFFER" ] && return
+
+	_aiterm_suggest_gen=$((_aiterm_suggest_gen + 1))
+	echo "$_aiterm_suggest_gen" > "$_aiterm_suggest_genfile"
+
+	_aiterm_suggest_for_buffer="$BUFFER"
+	_aiterm_suggest_outfile=$(mktemp)
+
+	local fd gen=$_aiterm_suggest_gen
+	exec {fd}< <(_aiterm_fetch_suggestion "$BUFFER" "$_aiterm_suggest_outfile" "$gen"; echo ready)
+	zle -F "$fd" _aiterm_suggestion_ready
+}
+
+_aiterm_self_insert() {
+	zle .self-insert
+	_aiterm_schedule_suggestion
+}
+zle -N self-insert _aiterm_self_insert
+
+_aiterm_backward_delete_char() {
+	zle .backward-delete-char
+	_aiterm_schedule_suggestion
+}
+zle -N backward-delete-char _aiterm_backward_delete_char
+
+# Right-Arrow / ^F: accept the whole suggestion when the cursor is at the
+# end of the buffer, otherwise behave like a normal forward-char.
+_aiterm_accept_suggestion() {
+	if [ -n "$_aiterm_suggestion" ] && [ $CURSOR -eq ${#BUFFER} ]; then
+		BUFFER="$BUFFER$_aiterm_suggestion"
+		CURSOR=${#BUFFER}
+		_aiterm_clear_suggestion
+	else
+		zle .forward-char
+	fi
+}
+zle -N _aiterm_accept_suggestion
+
+# Alt-F (^[f / ^[F): accept just the next word of the suggestion.
+_aiterm_accept_suggestion_word() {
+	if [ -n "$_aiterm_suggestion" ] && [ $CURSOR -eq ${#BUFFER} ]; then
+		local word="${_aiterm_suggestion%%%%[[:space:]]*}"
+		[ -z "$word" ] && word="$_aiterm_suggestion"
+		BUFFER="$BUFFER$word"
+		CURSOR=${#BUFFER}
+		_aiterm_suggestion="${_aiterm_suggestion#$word}"
+		_aiterm_suggestion="${_aiterm_suggestion# }"
+		POSTDISPLAY="$_aiterm_suggestion"
+	else
+		zle .forward-word
+	fi
+}
+zle -N _aiterm_accept_suggestion_word
+
+bindkey '^[[C' _aiterm_accept_suggestion
+bindkey '^F' _aiterm_accept_suggestion
+bindkey '^[f' _aiterm_accept_suggestion_word
+bindkey '^[F' _aiterm_accept_suggestion_word
+`
+
+// createZshWrapperScript creates a .zshrc wrapper that adds the AI
+// translation keybinding to the user's real zsh, via ZDOTDIR.
+func (s *ShellInterface) createZshWrapperScript() (string, error) {
 	tmpDir, err := os.MkdirTemp("", "aiterm-zsh-")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp dir: %w", err)
 	}
 
-	// Create wrapper based on shell type
-	if strings.Contains(s.shell, "zsh") {
-		// Create .zshrc wrapper
-		zshrcPath := filepath.Join(tmpDir, ".zshrc")
+	// Source original zshrc first, then add our binding
+	homeDir, _ := os.UserHomeDir()
 
-		// Source original zshrc first, then add our binding
-		homeDir, _ := os.UserHomeDir()
+	// Get absolute path to aiterm executable
+	aitermPath := aitermExecutablePath()
+	logger.Debug("Using aiterm path: %s", aitermPath)
 
-		// Get absolute path to aiterm executable
-		aitermPath, absErr := filepath.Abs(os.Args[0])
-		if absErr != nil {
-			aitermPath = os.Args[0]
-		}
-		// Quote the path for shell safety
-		aitermPath = fmt.Sprintf("\"%s\"", aitermPath)
-		logger.Debug("Using aiterm path: %s", aitermPath)
-
-		content := fmt.Sprintf(`# Source original zshrc
+	zshrcPath := filepath.Join(tmpDir, ".zshrc")
+	content := fmt.Sprintf(`# Source original zshrc
 if [ -f "%s/.zshrc" ]; then
 	source "%s/.zshrc"
 fi
@@ -106,14 +262,19 @@ ai-translate-command() {
 		return
 	fi
 
-	# Start translation in background for multiple options (max 5 most common commands)
+	# Stream translation candidates in the background; --ai-translate-multiple
+	# now prints one already-cleaned command per line as they're generated,
+	# so we just tail the file instead of polling a finished job and sed-
+	# cleaning its output.
 	local tmpfile=$(mktemp)
 	%s --ai-translate-multiple 5 "$current_buffer" < /dev/null > "$tmpfile" 2>&1 &
 	local job=$!
 
-	# Show spinner on a separate line below the prompt (braille characters)
 	local spinner=('⠋' '⠙' '⠹' '⠸' '⠼' '⠴' '⠦' '⠧' '⠇' '⠏')
 	local i=0
+	local -a options
+	local lines_shown=0
+	local cancelled=0
 
 	# Keep the natural language in BUFFER for now (ZLE already displayed it)
 	BUFFER="$current_buffer"
@@ -122,216 +283,90 @@ ai-translate-command() {
 	# Move to next line for spinner (don't overwrite the prompt)
 	echo "" >&2
 	while kill -0 $job 2>/dev/null; do
+		local new_line
+		for new_line in "${(@f)$(tail -n +$((lines_shown+1)) "$tmpfile" 2>/dev/null)}"; do
+			[ -z "$new_line" ] && continue
+			options+=("$new_line")
+			lines_shown=$((lines_shown+1))
+			echo -e "\r\033[K  [$lines_shown] $new_line" >&2
+		done
+
 		echo -ne "\r\033[K  Translating... ${spinner[i]}" >&2
 		i=$(( (i+1) %% 10 ))
-		sleep 0.05 || true
-	done
-	# Clear spinner line
-	echo -ne "\r\033[K" >&2
-	# Move back up to prompt line
-	echo -ne "\033[A" >&2
-	# Clear the prompt line with natural language immediately
-	echo -ne "\r\033[K" >&2
 
-	# Wait for job to complete
+		# Esc cancels: SIGINT the child so its AI request's context is
+		# cancelled instead of left running unattended in the background.
+		if read -t 0.05 -k 1 key 2>/dev/null && [ "$key" = $'\e' ]; then
+			kill -INT $job 2>/dev/null
+			cancelled=1
+			break
+		fi
+	done
 	wait $job 2>/dev/null || true
 
-	# Get result
-	local translated=$(cat $tmpfile)
-	rm $tmpfile
-
-	# Parse multiple options (separated by newlines)
-	local -a options
-	while IFS= read -r line; do
-		# Remove leading numbers with various formats: "1. ", "1) ", "1)", "1.", etc.
-		line=$(echo "$line" | sed -E 's/^[[:space:]]*[0-9]+[.)][[:space:]]*//')
-		# Trim whitespace
-		line=$(echo "$line" | sed 's/^[[:space:]]*//;s/[[:space:]]*$//')
-		# Skip empty lines, whitespace-only lines, or lines that are just numbers/punctuation
-		# Also ensure line has at least one alphanumeric character
-		if [ -n "$line" ] && echo "$line" | grep -qE '[[:alnum:]]' && ! echo "$line" | grep -qE '^[0-9]+[.)]?[[:space:]]*$'; then
-			options+=("$line")
-			# Limit to maximum 5 commands
-			if [ ${#options[@]} -ge 5 ]; then
-				break
-			fi
-		fi
-	done <<< "$translated"
+	# Pick up any candidates written after our last poll.
+	local trailing
+	for trailing in "${(@f)$(tail -n +$((lines_shown+1)) "$tmpfile" 2>/dev/null)}"; do
+		[ -z "$trailing" ] && continue
+		options+=("$trailing")
+		lines_shown=$((lines_shown+1))
+	done
+	rm -f "$tmpfile"
 
-	# Remove any empty strings that might have slipped through
-	# Create new array and rebuild from non-empty elements
-	local -a cleaned_options=()
+	# Clear the spinner line, every candidate line we printed above it, and
+	# finally the original prompt line (still showing the natural language).
+	echo -ne "\r\033[K" >&2
 	local idx
-	for ((idx=0; idx<${#options[@]}; idx++)); do
-		local opt="${options[$idx]}"
-		local stripped="${opt// /}"
-		# Skip if empty or only whitespace
-		if [[ -n "$stripped" ]]; then
-			cleaned_options+=("$opt")
-		fi
-	done
-	# Clear and rebuild original array - use 1-based indexing for zsh
-	options=()
-	for opt in "${cleaned_options[@]}"; do
-		options+=("$opt")
+	for ((idx=0; idx<lines_shown; idx++)); do
+		echo -ne "\033[A\r\033[K" >&2
 	done
+	echo -ne "\033[A\r\033[K" >&2
 
-	# Handle different cases: 0 options (do nothing), 1 option (use directly), multiple options (show menu)
+	if [ "$cancelled" -eq 1 ]; then
+		BUFFER="$current_buffer"
+		CURSOR=${#BUFFER}
+		zle -R
+		return 0
+	fi
+
+	# Handle different cases: 0 options (do nothing), 1 option (use directly),
+	# multiple options (let aiterm itself draw the picker on /dev/tty)
 	if [ ${#options[@]} -eq 0 ]; then
 		# No valid commands found - keep original buffer, do nothing
 		BUFFER="$current_buffer"
 		CURSOR=${#BUFFER}
-		zle -R
 	elif [ ${#options[@]} -eq 1 ]; then
 		# Single option - use it directly (zsh arrays are 1-indexed)
 		BUFFER="${options[1]}"
 		CURSOR=${#BUFFER}
-		zle -R
-	elif [ ${#options[@]} -gt 1 ]; then
-		local max_options=${#options[@]}
-
-		# Store options and state in global variables for widget access
-		# Note: zsh arrays are 1-indexed
-		typeset -g _aiterm_options=("${options[@]}")
-		typeset -g _aiterm_selected=1
-		typeset -g _aiterm_max_options=$max_options
-		typeset -g _aiterm_current_buffer="$current_buffer"
-		typeset -g _aiterm_selection_done=0
-		typeset -g _aiterm_menu_lines=$((max_options + 1))
-		
-		local SELECTED_COLOR=$'\033[1;32m'
-		local NORMAL_COLOR=$'\033[0m'
-		local INSTRUCTIONS_COLOR=$'\033[0;36m'
-		
-		# Save original cursor position BEFORE displaying menu (where user's prompt cursor was)
-		# Use \033[s which might support a stack better than DECSC
-		echo -ne "\033[s" >&2  # Save original cursor position
-		
-		# Function to display selection menu - redraw smoothly
-		_aiterm_display_menu() {
-			local menu_i option_text
-
-			# Move to saved menu start position (first menu line)
-			echo -ne "\033[u" >&2
-			# Move up one more line to the prompt
-			echo -ne "\033[A" >&2
-			# Go to beginning and clear to end of screen
-			echo -ne "\r\033[J" >&2
-
-			# Redraw prompt with currently selected command
-			print -P -n "$PROMPT" >&2
-			echo -n "${_aiterm_options[$_aiterm_selected]}" >&2
-			echo "" >&2  # Move to next line
-
-			# Display menu options (zsh arrays are 1-indexed)
-			for ((menu_i=1; menu_i<=_aiterm_max_options; menu_i++)); do
-				option_text="${_aiterm_options[$menu_i]}"
-				if [ $menu_i -eq $_aiterm_selected ]; then
-					echo "${SELECTED_COLOR}➤ ${option_text}${NORMAL_COLOR}" >&2
-				else
-					echo "  ${option_text}" >&2
-				fi
-			done
-			# Instructions line - no newline to prevent scroll
-			echo -n "${INSTRUCTIONS_COLOR}↑/↓: Navigate  Enter: Select  Esc/C: Cancel${NORMAL_COLOR}" >&2
-		}
-		
-		# Initial display - Update BUFFER but don't use zle -R (we'll manage display ourselves)
-		BUFFER="${options[1]}"
+	else
+		# aiterm draws the arrow-key menu itself and prints the chosen
+		# command on stdout; an empty result means the user cancelled.
+		local chosen
+		chosen=$(%s --ai-select "${options[@]}" < /dev/tty)
+		if [ -n "$chosen" ]; then
+			BUFFER="$chosen"
+		else
+			BUFFER="$current_buffer"
+		fi
 		CURSOR=${#BUFFER}
+	fi
 
-		# Print prompt with first selected command manually (prompt line already cleared)
-		print -P -n "$PROMPT" >&2
-		echo -n "${options[1]}" >&2
-		echo "" >&2  # Move to next line
-
-		# Save menu start position (at first option line)
-		echo -ne "\033[s" >&2
-		local menu_idx
-		for ((menu_idx=1; menu_idx<=max_options; menu_idx++)); do
-			option_text="${options[$menu_idx]}"
-			if [ $menu_idx -eq 1 ]; then
-				echo "${SELECTED_COLOR}➤ ${option_text}${NORMAL_COLOR}" >&2
-			else
-				echo "  ${option_text}" >&2
-			fi
-		done
-		echo "${INSTRUCTIONS_COLOR}↑/↓: Navigate  Enter: Select  Esc/C: Cancel${NORMAL_COLOR}" >&2
-		
-		# Wait for selection - use zsh's read -k reading from /dev/tty
-		# We'll read keys in a loop and update the display
-		while [ $_aiterm_selection_done -eq 0 ]; do
-			# Use read -k to read a single key from /dev/tty (non-blocking with timeout)
-			read -k 1 -t 0.1 key < /dev/tty 2>/dev/null || {
-				# Timeout - continue loop to check selection_done
-				continue
-			}
-			
-			case "$key" in
-				$'\e')
-					# Escape sequence - read next char from /dev/tty
-					read -k 1 -t 0.1 key2 < /dev/tty 2>/dev/null || {
-						# Just Escape - cancel
-						_aiterm_selection_done=1
-						BUFFER="$_aiterm_current_buffer"
-						CURSOR=${#BUFFER}
-						break
-					}
-					if [ "$key2" = '[' ]; then
-						read -k 1 -t 0.1 key3 < /dev/tty 2>/dev/null || break
-						case "$key3" in
-							'A') # Up arrow
-								if [ $_aiterm_selected -gt 1 ]; then
-									_aiterm_selected=$((_aiterm_selected - 1))
-									# Update BUFFER with currently selected command
-									BUFFER="${_aiterm_options[$_aiterm_selected]}"
-									CURSOR=${#BUFFER}
-									# Update the display (redraws prompt + menu)
-									_aiterm_display_menu
-								fi
-								;;
-							'B') # Down arrow
-								if [ $_aiterm_selected -lt $_aiterm_max_options ]; then
-									_aiterm_selected=$((_aiterm_selected + 1))
-									# Update BUFFER with currently selected command
-									BUFFER="${_aiterm_options[$_aiterm_selected]}"
-									CURSOR=${#BUFFER}
-									# Update the display (redraws prompt + menu)
-									_aiterm_display_menu
-								fi
-								;;
-						esac
-					else
-						# Other escape - cancel
-						_aiterm_selection_done=1
-						BUFFER="$_aiterm_current_buffer"
-						CURSOR=${#BUFFER}
-						break
-					fi
-					;;
-				$'\n'|$'\r')
-					# Enter - accept
-					_aiterm_selection_done=1
-					BUFFER="${_aiterm_options[$_aiterm_selected]}"
-					CURSOR=${#BUFFER}
-					break
-					;;
-				'c'|'C')
-					# Cancel
-					_aiterm_selection_done=1
-					BUFFER="$_aiterm_current_buffer"
-					CURSOR=${#BUFFER}
-					break
-					;;
-			esac
-		done
-		
-		# Clear menu - cursor is at end of instructions line
-		# Move cursor up to the prompt line (prompt + options + instructions = max_options + 2 lines total)
-		local clear_lines=$((_aiterm_max_options + 2))
-		echo -ne "\033[${clear_lines}A" >&2
-		# Move to beginning of line and clear everything to end of screen
-		echo -ne "\r\033[J" >&2
+	# Destructive commands are prefixed "[confirm] " by the translate
+	# pipeline (see AnnotateConfirmPrefix); require an explicit y/N before
+	# accepting one into BUFFER.
+	if [[ "$BUFFER" == "[confirm] "* ]]; then
+		local candidate="${BUFFER#\[confirm\] }"
+		echo -n "Run (possibly destructive): $candidate [y/N] " >&2
+		local confirm_key
+		read -k 1 confirm_key
+		echo "" >&2
+		if [[ "$confirm_key" == [yY] ]]; then
+			BUFFER="$candidate"
+		else
+			BUFFER="$current_buffer"
+		fi
+		CURSOR=${#BUFFER}
 	fi
 
 	# Redraw prompt with zle (BUFFER already has the selected command)
@@ -405,28 +440,313 @@ add-zsh-hook precmd aiterm-setup-bindings-hook >/dev/null 2>&1
 # Note: Ctrl+Tab is often intercepted by terminal emulators and may not work
 # Uncomment the following line if your terminal supports it:
 # bindkey '^[^I' ai-translate-command
-`, homeDir, homeDir, aitermPath)
 
-		err = os.WriteFile(zshrcPath, []byte(content), 0600)
-		if err != nil {
-			return "", fmt.Errorf("failed to write zshrc: %w", err)
-		}
+%s`, homeDir, homeDir, aitermPath, aitermPath, s.autosuggestBlock(aitermPath))
+
+	err = os.WriteFile(zshrcPath, []byte(content), 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to write zshrc: %w", err)
+	}
+
+	logger.Debug("Created wrapper at %s", tmpDir)
+
+	// Also write the content to a persistent file for debugging
+	debugPath := "/tmp/aiterm-debug-wrapper.zsh"
+	if writeErr := os.WriteFile(debugPath, []byte(content), 0644); writeErr != nil {
+		logger.Debug("Failed to write debug wrapper: %v", writeErr)
+	} else {
+		logger.Debug("Debug wrapper written to: %s", debugPath)
+	}
+
+	return tmpDir, nil
+}
+
+// createBashWrapperScript creates a .bashrc that the shell is started with
+// via `bash --rcfile`, installing a readline `bind -x` binding in place of
+// zsh's ZLE widget.
+func (s *ShellInterface) createBashWrapperScript() (string, error) {
+	tmpDir, err := os.MkdirTemp("", "aiterm-bash-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	aitermPath := aitermExecutablePath()
+	logger.Debug("Using aiterm path: %s", aitermPath)
+
+	bashrcPath := filepath.Join(tmpDir, ".bashrc")
+	content := fmt.Sprintf(`# Source original bashrc
+if [ -f "%s/.bashrc" ]; then
+	source "%s/.bashrc"
+fi
+
+# AI translation function, bound to a readline key below
+_aiterm_translate_command() {
+	local current_buffer="$READLINE_LINE"
+
+	if [ -z "$current_buffer" ]; then
+		return
+	fi
+
+	# Start translation in background for multiple options (max 5 most common commands)
+	local tmpfile=$(mktemp)
+	%s --ai-translate-multiple 5 "$current_buffer" < /dev/null > "$tmpfile" 2>&1 &
+	local job=$!
+
+	# Show spinner on a separate line below the prompt (braille characters)
+	local spinner=('⠋' '⠙' '⠹' '⠸' '⠼' '⠴' '⠦' '⠧' '⠇' '⠏')
+	local i=0
+	local -a options=()
+	local lines_shown=0
+	local cancelled=0
+
+	echo "" >&2
+	while kill -0 $job 2>/dev/null; do
+		local -a new_lines=()
+		mapfile -t new_lines < <(tail -n +$((lines_shown+1)) "$tmpfile" 2>/dev/null)
+		local new_line
+		for new_line in "${new_lines[@]}"; do
+			[ -z "$new_line" ] && continue
+			options+=("$new_line")
+			lines_shown=$((lines_shown+1))
+			echo -e "\r\033[K  [$lines_shown] $new_line" >&2
+		done
+
+		echo -ne "\r\033[K  Translating... ${spinner[i]}" >&2
+		i=$(( (i+1) %% 10 ))
+
+		# Esc cancels: SIGINT the child so its AI request's context is
+		# cancelled instead of left running unattended in the background.
+		local key=""
+		if read -t 0.05 -n 1 -s key 2>/dev/null && [ "$key" = $'\e' ]; then
+			kill -INT $job 2>/dev/null
+			cancelled=1
+			break
+		fi
+	done
+	wait $job 2>/dev/null || true
+
+	# Pick up any candidates written after our last poll.
+	local -a trailing=()
+	mapfile -t trailing < <(tail -n +$((lines_shown+1)) "$tmpfile" 2>/dev/null)
+	local t
+	for t in "${trailing[@]}"; do
+		[ -z "$t" ] && continue
+		options+=("$t")
+		lines_shown=$((lines_shown+1))
+	done
+	rm -f "$tmpfile"
 
-		logger.Debug("Created wrapper at %s", tmpDir)
+	# Clear the spinner line, every candidate line we printed above it, and
+	# finally the original prompt line (still showing the natural language).
+	echo -ne "\r\033[K" >&2
+	local idx
+	for ((idx=0; idx<lines_shown; idx++)); do
+		echo -ne "\033[A\r\033[K" >&2
+	done
+	echo -ne "\033[A\r\033[K" >&2
 
-		// Also write the content to a persistent file for debugging
-		debugPath := "/tmp/aiterm-debug-wrapper.zsh"
-		if writeErr := os.WriteFile(debugPath, []byte(content), 0644); writeErr != nil {
-			logger.Debug("Failed to write debug wrapper: %v", writeErr)
-		} else {
-			logger.Debug("Debug wrapper written to: %s", debugPath)
-		}
+	if [ "$cancelled" -eq 1 ]; then
+		READLINE_LINE="$current_buffer"
+		READLINE_POINT=${#READLINE_LINE}
+		return 0
+	fi
 
-		return tmpDir, nil
+	# Handle different cases: 0 options (do nothing), 1 option (use directly),
+	# multiple options (let aiterm draw the picker on /dev/tty)
+	if [ ${#options[@]} -eq 0 ]; then
+		READLINE_LINE="$current_buffer"
+	elif [ ${#options[@]} -eq 1 ]; then
+		READLINE_LINE="${options[0]}"
+	else
+		local chosen
+		chosen=$(%s --ai-select "${options[@]}" < /dev/tty)
+		if [ -n "$chosen" ]; then
+			READLINE_LINE="$chosen"
+		else
+			READLINE_LINE="$current_buffer"
+		fi
+	fi
+
+	# Destructive commands are prefixed "[confirm] " by the translate
+	# pipeline (see AnnotateConfirmPrefix); require an explicit y/N before
+	# accepting one into READLINE_LINE.
+	if [[ "$READLINE_LINE" == "[confirm] "* ]]; then
+		local candidate="${READLINE_LINE#\[confirm\] }"
+		echo -n "Run (possibly destructive): $candidate [y/N] " >&2
+		local confirm_key=""
+		read -n 1 -s confirm_key
+		echo "" >&2
+		if [[ "$confirm_key" =~ [yY] ]]; then
+			READLINE_LINE="$candidate"
+		else
+			READLINE_LINE="$current_buffer"
+		fi
+	fi
+	READLINE_POINT=${#READLINE_LINE}
+}
+
+# Bind to Ctrl+X then Ctrl+A, same as the zsh wrapper
+bind -x '"\C-x\C-a": _aiterm_translate_command' 2>/dev/null || true
+`, homeDir, homeDir, aitermPath, aitermPath)
+
+	err = os.WriteFile(bashrcPath, []byte(content), 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to write bashrc: %w", err)
+	}
+
+	logger.Debug("Created wrapper at %s", tmpDir)
+
+	debugPath := "/tmp/aiterm-debug-wrapper.bash"
+	if writeErr := os.WriteFile(debugPath, []byte(content), 0644); writeErr != nil {
+		logger.Debug("Failed to write debug wrapper: %v", writeErr)
+	} else {
+		logger.Debug("Debug wrapper written to: %s", debugPath)
+	}
+
+	return tmpDir, nil
+}
+
+// createFishWrapperScript creates a fish config, dropped at
+// $XDG_CONFIG_HOME/fish/config.fish, with a `commandline -r`-driven
+// equivalent of the zsh/bash translation keybinding.
+func (s *ShellInterface) createFishWrapperScript() (string, error) {
+	tmpDir, err := os.MkdirTemp("", "aiterm-fish-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	fishConfigDir := filepath.Join(tmpDir, "fish")
+	if err := os.MkdirAll(fishConfigDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create fish config dir: %w", err)
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	aitermPath := aitermExecutablePath()
+	logger.Debug("Using aiterm path: %s", aitermPath)
+
+	configPath := filepath.Join(fishConfigDir, "config.fish")
+	content := fmt.Sprintf(`# Source original fish config
+if test -f "%s/.config/fish/config.fish"
+	source "%s/.config/fish/config.fish"
+end
+
+function _aiterm_translate_command
+	set -l current_buffer (commandline)
+
+	if test -z "$current_buffer"
+		return
+	end
+
+	# Start translation in background for multiple options (max 5 most common commands)
+	set -l tmpfile (mktemp)
+	%s --ai-translate-multiple 5 "$current_buffer" < /dev/null > $tmpfile 2>&1 &
+	set -l job $last_pid
+
+	# Show spinner on a separate line below the prompt (braille characters)
+	set -l spinner ⠋ ⠙ ⠹ ⠸ ⠼ ⠴ ⠦ ⠧ ⠇ ⠏
+	set -l i 1
+
+	set -l options
+	set -l lines_shown 0
+	set -l cancelled 0
+
+	echo "" >&2
+	while kill -0 $job 2>/dev/null
+		for new_line in (tail -n +(math "$lines_shown + 1") $tmpfile 2>/dev/null)
+			test -z "$new_line"; and continue
+			set options $options $new_line
+			set lines_shown (math "$lines_shown + 1")
+			printf "\r\033[K  [%%d] %%s\n" $lines_shown $new_line >&2
+		end
+
+		printf "\r\033[K  Translating... %%s" $spinner[$i] >&2
+		set i (math "$i %% 10 + 1")
+
+		# Esc cancels: SIGINT the child so its AI request's context is
+		# cancelled instead of left running unattended in the background.
+		if read -t 0.05 -n 1 key 2>/dev/null; and test "$key" = \e
+			kill -INT $job 2>/dev/null
+			set cancelled 1
+			break
+		end
+	end
+	wait $job 2>/dev/null
+
+	# Pick up any candidates written after our last poll.
+	for line in (tail -n +(math "$lines_shown + 1") $tmpfile 2>/dev/null)
+		test -z "$line"; and continue
+		set options $options $line
+		set lines_shown (math "$lines_shown + 1")
+	end
+	rm -f $tmpfile
+
+	# Clear the spinner line, every candidate line we printed above it, and
+	# finally the original prompt line (still showing the natural language).
+	printf "\r\033[K" >&2
+	for clear_i in (seq 1 $lines_shown)
+		printf "\033[A\r\033[K" >&2
+	end
+	printf "\033[A\r\033[K" >&2
+
+	if test "$cancelled" -eq 1
+		commandline -r $current_buffer
+		return 0
+	end
+
+	# Handle different cases: 0 options (do nothing), 1 option (use directly),
+	# multiple options (let aiterm draw the picker on /dev/tty)
+	set -l result
+	if test (count $options) -eq 0
+		set result $current_buffer
+	else if test (count $options) -eq 1
+		set result $options[1]
+	else
+		set -l chosen (%s --ai-select $options < /dev/tty)
+		if test -n "$chosen"
+			set result $chosen
+		else
+			set result $current_buffer
+		end
+	end
+
+	# Destructive commands are prefixed "[confirm] " by the translate
+	# pipeline (see AnnotateConfirmPrefix); require an explicit y/N before
+	# accepting one into the command line.
+	if string match -q -- "\[confirm\] *" $result
+		set -l candidate (string replace -r -- '^\[confirm\] ' '' $result)
+		printf "Run (possibly destructive): %%s [y/N] " $candidate >&2
+		read -n 1 -s confirm_key
+		echo "" >&2
+		if test "$confirm_key" = y -o "$confirm_key" = Y
+			set result $candidate
+		else
+			set result $current_buffer
+		end
+	end
+
+	commandline -r $result
+end
+
+# Bind to Ctrl+X then Ctrl+A, same as the zsh/bash wrappers
+bind \cx\ca _aiterm_translate_command
+`, homeDir, homeDir, aitermPath, aitermPath)
+
+	err = os.WriteFile(configPath, []byte(content), 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to write fish config: %w", err)
+	}
+
+	logger.Debug("Created wrapper at %s", tmpDir)
+
+	debugPath := "/tmp/aiterm-debug-wrapper.fish"
+	if writeErr := os.WriteFile(debugPath, []byte(content), 0644); writeErr != nil {
+		logger.Debug("Failed to write debug wrapper: %v", writeErr)
+	} else {
+		logger.Debug("Debug wrapper written to: %s", debugPath)
 	}
 
-	// TODO: Add bash support
-	return "", fmt.Errorf("shell wrapper not implemented for %s yet", s.shell)
+	return tmpDir, nil
 }
 
 func TranslateNaturalLanguage(mgr *Manager, naturalLanguage string) (string, error) {
@@ -437,14 +757,14 @@ func TranslateNaturalLanguage(mgr *Manager, naturalLanguage string) (string, err
 	}
 
 	cwd, _ := os.Getwd()
-	systemPrompt := fmt.Sprintf(`You are a shell command translator. Convert natural language to shell commands.
+	systemPrompt := fmt.Sprintf(`%s
 
 Operating System: %s
 Shell: %s
 Current Directory: %s
 
 Rules:
-1. Output ONLY a single shell command, nothing else
+1. %s
 2. No explanations, no comments, no markdown
 3. Command should be safe and follow best practices
 
@@ -455,7 +775,7 @@ Output: ls -la
 Input: "find python files"
 Output: find . -name "*.py"
 
-Respond with ONLY the command.`, mgr.OS, shellPath, cwd)
+Respond with ONLY the command.`, i18n.Get("prompt.translate_single_system"), mgr.OS, shellPath, cwd, i18n.Get("prompt.translate_single_rules"))
 
 	userPrompt := fmt.Sprintf("Translate: %s", naturalLanguage)
 
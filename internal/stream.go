@@ -0,0 +1,399 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// Chunk represents a single streamed token delta from an AI provider.
+type Chunk struct {
+	Delta        string
+	FinishReason string
+	Err          error
+}
+
+// chatCompletionStreamChoice is one entry of the "choices" array in a
+// chat-completions SSE frame.
+type chatCompletionStreamChoice struct {
+	Delta struct {
+		Content string `json:"content"`
+	} `json:"delta"`
+	FinishReason string `json:"finish_reason"`
+}
+
+// chatCompletionStreamChunk is a single `data: {...}` SSE frame emitted by
+// the chat completions endpoint when `stream: true` is set.
+type chatCompletionStreamChunk struct {
+	Choices []chatCompletionStreamChoice `json:"choices"`
+}
+
+// responseStreamEvent is a single `data: {...}` SSE frame emitted by the
+// Responses API when `stream: true` is set. Only the fields needed to
+// surface incremental text are parsed.
+type responseStreamEvent struct {
+	Type  string `json:"type"`
+	Delta string `json:"delta"`
+}
+
+// ChatCompletionStream sends a streaming chat completion request and returns
+// a channel of Chunk values as tokens arrive. The channel is closed once the
+// stream ends (either via a finish reason, `data: [DONE]`, or an error).
+func (c *AiClient) ChatCompletionStream(ctx context.Context, messages []Message, model string) (<-chan Chunk, error) {
+	reqBody := ChatCompletionRequest{
+		Model:    model,
+		Messages: messages,
+		Stream:   true,
+	}
+
+	// Get model configuration
+	var provider string
+	var apiKey string
+	var baseURL string
+	var apiBase string
+	var apiVersion string
+	var deploymentName string
+
+	// A YAML-defined backend profile matching the model name takes priority
+	// over both the config manager and the legacy configuration, mirroring
+	// determineAPIType's precedence.
+	if c.backends != nil {
+		if backend, exists := c.backends.GetByName(model); exists {
+			provider = backend.Provider
+			apiKey = backend.APIKey()
+			baseURL = backend.BaseURL
+			backend.Parameters.ApplyToChatRequest(&reqBody)
+		}
+	}
+
+	if provider == "" && c.configMgr != nil {
+		if modelConfig, exists := c.configMgr.GetCurrentModelConfig(); exists {
+			provider = modelConfig.Provider
+			apiKey = modelConfig.APIKey
+			baseURL = modelConfig.BaseURL
+			apiBase = modelConfig.APIBase
+			apiVersion = modelConfig.APIVersion
+			deploymentName = modelConfig.DeploymentName
+		}
+	}
+
+	if provider == "" {
+		if c.config.AzureOpenAI.APIKey != "" {
+			provider = "azure"
+			apiKey = c.config.AzureOpenAI.APIKey
+			apiBase = c.config.AzureOpenAI.APIBase
+			apiVersion = c.config.AzureOpenAI.APIVersion
+			deploymentName = c.config.AzureOpenAI.DeploymentName
+		} else if c.config.OpenRouter.APIKey != "" {
+			provider = "openrouter"
+			apiKey = c.config.OpenRouter.APIKey
+			baseURL = c.config.OpenRouter.BaseURL
+		}
+	}
+
+	var url string
+	var apiKeyHeader string
+
+	if provider == "azure" {
+		base := strings.TrimSuffix(apiBase, "/")
+		url = fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			base,
+			deploymentName,
+			apiVersion)
+		apiKeyHeader = "api-key"
+		reqBody.Model = ""
+	} else {
+		if baseURL == "" {
+			switch provider {
+			case "requesty":
+				baseURL = "https://router.requesty.ai/v1"
+			case "zai":
+				baseURL = "https://api.zai.com/v1"
+			case "xai":
+				baseURL = "https://api.x.ai/v1"
+			case "alibaba":
+				baseURL = "https://dashscope.aliyuncs.com/compatible-mode/v1"
+			default:
+				baseURL = c.config.OpenRouter.BaseURL
+			}
+		}
+		base := strings.TrimSuffix(baseURL, "/")
+		url = base + "/chat/completions"
+		apiKeyHeader = "Authorization"
+		apiKey = "Bearer " + apiKey
+	}
+
+	reqJSON, err := json.Marshal(reqBody)
+	if err != nil {
+		logger.Error("Failed to marshal streaming request: %v", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqJSON))
+	if err != nil {
+		logger.Error("Failed to create streaming request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(apiKeyHeader, apiKey)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("HTTP-Referer", "https://github.com/andreim2k/aiterm")
+	req.Header.Set("X-Title", "AITerm")
+
+	logger.Debug("Sending streaming API request to: %s with model: %s", url, model)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.Error("Failed to send streaming request: %v", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		logger.Error("Streaming API returned error: %s", body)
+		return nil, fmt.Errorf("API returned error: %s", body)
+	}
+
+	chunks := make(chan Chunk)
+
+	go func() {
+		defer close(chunks)
+		defer func() { _ = resp.Body.Close() }()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			data := strings.TrimPrefix(line, "data: ")
+			if data == "[DONE]" {
+				return
+			}
+
+			var frame chatCompletionStreamChunk
+			if err := json.Unmarshal([]byte(data), &frame); err != nil {
+				logger.Debug("Failed to unmarshal SSE frame: %v, data: %s", err, data)
+				continue
+			}
+			if len(frame.Choices) == 0 {
+				continue
+			}
+
+			choice := frame.Choices[0]
+			chunks <- Chunk{Delta: choice.Delta.Content, FinishReason: choice.FinishReason}
+			if choice.FinishReason != "" {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			chunks <- Chunk{Err: fmt.Errorf("streaming read failed: %w", err)}
+		}
+	}()
+
+	return chunks, nil
+}
+
+// ResponseStream sends a streaming request to the Responses API and returns
+// a channel of Chunk values as output text deltas arrive.
+func (c *AiClient) ResponseStream(ctx context.Context, messages []Message, model string) (<-chan Chunk, error) {
+	var input ResponseInput
+	var instructions string
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages provided")
+	}
+
+	if messages[0].Role == "system" {
+		instructions = messages[0].Content
+		if len(messages) > 1 {
+			input = messages[1:]
+		} else {
+			return nil, fmt.Errorf("only system message provided, no user message to process")
+		}
+	} else {
+		input = messages
+	}
+
+	reqBody := ResponseRequest{
+		Model:        model,
+		Input:        input,
+		Instructions: instructions,
+		Store:        false,
+		Stream:       true,
+	}
+
+	var apiKey string
+	var baseURL string
+
+	// A YAML-defined backend profile matching the model name takes priority,
+	// mirroring determineAPIType's precedence.
+	if c.backends != nil {
+		if backend, exists := c.backends.GetByName(model); exists && backend.Provider == "openai" {
+			apiKey = backend.APIKey()
+			baseURL = backend.BaseURL
+			backend.Parameters.ApplyToResponseRequest(&reqBody)
+		}
+	}
+
+	if apiKey == "" && c.configMgr != nil {
+		if modelConfig, exists := c.configMgr.GetCurrentModelConfig(); exists && modelConfig.Provider == "openai" {
+			apiKey = modelConfig.APIKey
+			baseURL = modelConfig.BaseURL
+		}
+	}
+
+	if apiKey == "" {
+		apiKey = c.config.OpenAI.APIKey
+	}
+	if baseURL == "" {
+		baseURL = c.config.OpenAI.BaseURL
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	url := baseURL + "/responses"
+
+	reqJSON, err := json.Marshal(reqBody)
+	if err != nil {
+		logger.Error("Failed to marshal Responses API streaming request: %v", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqJSON))
+	if err != nil {
+		logger.Error("Failed to create Responses API streaming request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("HTTP-Referer", "https://github.com/andreim2k/aiterm")
+	req.Header.Set("X-Title", "AITerm")
+
+	logger.Debug("Sending Responses API streaming request to: %s with model: %s", url, model)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.Error("Failed to send Responses API streaming request: %v", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		logger.Error("Responses API streaming returned error: %s", body)
+		return nil, fmt.Errorf("API returned error: %s", body)
+	}
+
+	chunks := make(chan Chunk)
+
+	go func() {
+		defer close(chunks)
+		defer func() { _ = resp.Body.Close() }()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			data := strings.TrimPrefix(line, "data: ")
+			if data == "[DONE]" {
+				return
+			}
+
+			var event responseStreamEvent
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				logger.Debug("Failed to unmarshal Responses API SSE event: %v, data: %s", err, data)
+				continue
+			}
+
+			switch event.Type {
+			case "response.output_text.delta":
+				chunks <- Chunk{Delta: event.Delta}
+			case "response.completed", "response.failed", "response.incomplete":
+				chunks <- Chunk{FinishReason: event.Type}
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			chunks <- Chunk{Err: fmt.Errorf("streaming read failed: %w", err)}
+		}
+	}()
+
+	return chunks, nil
+}
+
+// GetResponseFromChatMessagesStream behaves like GetResponseFromChatMessages
+// but streams incremental Chunk values to onChunk as they arrive instead of
+// blocking until the full completion is available, so an interactive TUI can
+// render tokens live.
+func (c *AiClient) GetResponseFromChatMessagesStream(ctx context.Context, chatMessages []ChatMessage, model string, onChunk func(Chunk)) error {
+	aiMessages := []Message{}
+
+	for i, msg := range chatMessages {
+		var role string
+
+		if i == 0 && !msg.FromUser {
+			role = "system"
+		} else if msg.FromUser {
+			role = "user"
+		} else {
+			role = "assistant"
+		}
+
+		aiMessages = append(aiMessages, Message{
+			Role:    role,
+			Content: msg.Content,
+		})
+	}
+
+	logger.Info("Streaming %d messages to AI using model: %s", len(aiMessages), model)
+
+	apiType := c.determineAPIType(model)
+	logger.Debug("Using API type: %s for model: %s (streaming)", apiType, model)
+
+	var chunks <-chan Chunk
+	var err error
+
+	switch apiType {
+	case "responses":
+		chunks, err = c.ResponseStream(ctx, aiMessages, model)
+	case "azure", "openrouter", "requesty", "zai", "xai", "alibaba":
+		chunks, err = c.ChatCompletionStream(ctx, aiMessages, model)
+	default:
+		return fmt.Errorf("unknown API type: %s", apiType)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return chunk.Err
+		}
+		onChunk(chunk)
+	}
+
+	return nil
+}
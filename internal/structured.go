@@ -0,0 +1,420 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/andreim2k/aiterm/internal/i18n"
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// CommandOption is a single structured command suggestion returned by the
+// model when response_format / the Responses API's text.format is set to a
+// json_schema, replacing the regex/prefix-stripping parser for providers
+// that honor it.
+type CommandOption struct {
+	Cmd         string `json:"cmd"`
+	Explanation string `json:"explanation"`
+	Risk        string `json:"risk"` // low|medium|high
+}
+
+type commandOptionsEnvelope struct {
+	Commands []CommandOption `json:"commands"`
+}
+
+// Suggestion is a single extracted command option ready for shell
+// integration, regardless of whether it came from the structured JSON
+// envelope or the heuristic prose parser. Explanation and Risk are empty
+// when the heuristic parser had to be used, since it has no way to recover
+// either from freeform text.
+type Suggestion struct {
+	Cmd         string
+	Explanation string
+	Risk        string // low|medium|high, self-reported by the model, empty if unknown
+
+	// ClassifiedRisk is set by ClassifySuggestions, which runs the command
+	// through a RiskPolicy's rules table independent of whatever Risk the
+	// model itself claimed, so downstream shell integration has a
+	// deterministic basis for warning on or refusing destructive commands.
+	ClassifiedRisk RiskLevel
+}
+
+// SuggestCommands returns count shell command suggestions for naturalLanguage
+// as Suggestions, preferring the structured JSON envelope (which carries an
+// explanation and risk level per command) and falling back to the heuristic
+// prose parser, in which case Explanation and Risk are left empty.
+func (c *AiClient) SuggestCommands(naturalLanguage, osName, shellPath, cwd, model string, count int) ([]Suggestion, error) {
+	// TranslateNaturalLanguageMultiple already prefers the structured JSON
+	// envelope and only falls back to the heuristic parser itself, so a
+	// single call here carries Explanation/Risk through whichever path won.
+	options, err := c.TranslateNaturalLanguageMultiple(naturalLanguage, osName, shellPath, cwd, model, count)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]Suggestion, 0, len(options))
+	for _, opt := range options {
+		suggestions = append(suggestions, Suggestion{Cmd: opt.Cmd, Explanation: opt.Explanation, Risk: opt.Risk})
+	}
+	return suggestions, nil
+}
+
+// RunSuggestCommands is the entry point for --ai-suggest-commands combined
+// with --confirm=<level>/--deny=<level>. It fetches count suggestions for
+// naturalLanguage, classifies each with the user's RiskPolicy (ClassifySuggestions),
+// and writes one per line to w, skipping any EnforcePolicy denies under mode
+// and threshold and prefixing "[confirm] " on any that need an explicit
+// y/N from the shell wrapper before executing.
+func RunSuggestCommands(mgr *Manager, naturalLanguage string, count int, mode PolicyMode, threshold RiskLevel, w io.Writer) error {
+	shellPath := os.Getenv("SHELL")
+	cwd, _ := os.Getwd()
+
+	suggestions, err := mgr.AiClient.SuggestCommands(naturalLanguage, mgr.OS, shellPath, cwd, mgr.GetModel(), count)
+	if err != nil {
+		return err
+	}
+
+	policy, err := LoadUserRiskPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to load risk policy: %w", err)
+	}
+	suggestions = ClassifySuggestions(policy, suggestions)
+
+	for _, s := range suggestions {
+		allow, confirm := EnforcePolicy(mode, threshold, s)
+		if !allow {
+			logger.Debug("Suggestion %q denied by policy (risk: %s)", s.Cmd, s.ClassifiedRisk)
+			continue
+		}
+		line := s.Cmd
+		if confirm {
+			line = "[confirm] " + line
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commandOptionsSchema is the JSON schema both APIs are asked to conform to.
+func commandOptionsSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"commands": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"cmd":         map[string]interface{}{"type": "string"},
+						"explanation": map[string]interface{}{"type": "string"},
+						"risk":        map[string]interface{}{"type": "string", "enum": []string{"low", "medium", "high"}},
+					},
+					"required": []string{"cmd", "explanation", "risk"},
+				},
+			},
+		},
+		"required": []string{"commands"},
+	}
+}
+
+// TranslateNaturalLanguageMultipleStructured asks the model for `count`
+// command options as a strict JSON envelope and parses it with
+// encoding/json. It returns an error if the provider doesn't honor the
+// requested format or the reply doesn't validate, so the caller can fall
+// back to TranslateNaturalLanguageMultiple's heuristic parser.
+func (c *AiClient) TranslateNaturalLanguageMultipleStructured(naturalLanguage, osName, shellPath, cwd, model string, count int) ([]CommandOption, error) {
+	if shellPath == "" {
+		shellPath = "/bin/bash"
+	}
+
+	systemPrompt := fmt.Sprintf(`%s Given a task, respond with a JSON object of the form {"commands":[{"cmd":"...","explanation":"...","risk":"low|medium|high"}]} containing the %d most commonly used commands for the task, most common first. Output ONLY the JSON object, nothing else.
+
+Operating System: %s
+Shell: %s
+Current Directory: %s`, i18n.Get("prompt.shell_generator"), count, osName, shellPath, cwd)
+
+	userPrompt := fmt.Sprintf("Task: %s", naturalLanguage)
+
+	aiMessages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userPrompt},
+	}
+
+	ctx := context.Background()
+	apiType := c.determineAPIType(model)
+
+	actualModel := model
+	if c.configMgr != nil {
+		if modelConfig, exists := c.configMgr.GetModelConfig(model); exists {
+			actualModel = modelConfig.Model
+		}
+	}
+
+	var raw string
+	var err error
+
+	switch apiType {
+	case "responses":
+		raw, err = c.responseStructured(ctx, aiMessages, actualModel)
+	default:
+		raw, err = c.chatCompletionStructured(ctx, aiMessages, actualModel)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope commandOptionsEnvelope
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &envelope); err != nil {
+		return nil, fmt.Errorf("structured output did not validate: %w", err)
+	}
+	if len(envelope.Commands) == 0 {
+		return nil, fmt.Errorf("structured output contained no commands")
+	}
+
+	return envelope.Commands, nil
+}
+
+// chatCompletionStructured performs a single chat-completions round trip
+// with response_format set to the command-options JSON schema and returns
+// the raw content string.
+func (c *AiClient) chatCompletionStructured(ctx context.Context, messages []Message, model string) (string, error) {
+	reqBody := ChatCompletionRequest{
+		Model:    model,
+		Messages: messages,
+		ResponseFormat: &ChatResponseFormatSchema{
+			Type: "json_schema",
+			JSONSchema: JSONSchemaSpec{
+				Name:   "command_options",
+				Strict: true,
+				Schema: commandOptionsSchema(),
+			},
+		},
+	}
+
+	var provider string
+	var apiKey string
+	var baseURL string
+	var apiBase string
+	var apiVersion string
+	var deploymentName string
+
+	if c.configMgr != nil {
+		if modelConfig, exists := c.configMgr.GetCurrentModelConfig(); exists {
+			provider = modelConfig.Provider
+			apiKey = modelConfig.APIKey
+			baseURL = modelConfig.BaseURL
+			apiBase = modelConfig.APIBase
+			apiVersion = modelConfig.APIVersion
+			deploymentName = modelConfig.DeploymentName
+		}
+	}
+
+	if provider == "" {
+		if c.config.AzureOpenAI.APIKey != "" {
+			provider = "azure"
+			apiKey = c.config.AzureOpenAI.APIKey
+			apiBase = c.config.AzureOpenAI.APIBase
+			apiVersion = c.config.AzureOpenAI.APIVersion
+			deploymentName = c.config.AzureOpenAI.DeploymentName
+		} else if c.config.OpenRouter.APIKey != "" {
+			provider = "openrouter"
+			apiKey = c.config.OpenRouter.APIKey
+			baseURL = c.config.OpenRouter.BaseURL
+		}
+	}
+
+	var url string
+	var apiKeyHeader string
+
+	if provider == "azure" {
+		base := strings.TrimSuffix(apiBase, "/")
+		url = fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			base,
+			deploymentName,
+			apiVersion)
+		apiKeyHeader = "api-key"
+		reqBody.Model = ""
+	} else {
+		if baseURL == "" {
+			switch provider {
+			case "requesty":
+				baseURL = "https://router.requesty.ai/v1"
+			case "zai":
+				baseURL = "https://api.zai.com/v1"
+			case "xai":
+				baseURL = "https://api.x.ai/v1"
+			case "alibaba":
+				baseURL = "https://dashscope.aliyuncs.com/compatible-mode/v1"
+			default:
+				baseURL = c.config.OpenRouter.BaseURL
+			}
+		}
+		base := strings.TrimSuffix(baseURL, "/")
+		url = base + "/chat/completions"
+		apiKeyHeader = "Authorization"
+		apiKey = "Bearer " + apiKey
+	}
+
+	reqJSON, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqJSON))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(apiKeyHeader, apiKey)
+	req.Header.Set("HTTP-Referer", "https://github.com/andreim2k/aiterm")
+	req.Header.Set("X-Title", "AITerm")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", parseAPIError(resp.StatusCode, body)
+	}
+
+	var completionResp ChatCompletionResponse
+	if err := json.Unmarshal(body, &completionResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(completionResp.Choices) == 0 {
+		return "", fmt.Errorf("no completion choices returned (model: %s, status: %d)", model, resp.StatusCode)
+	}
+
+	return completionResp.Choices[0].Message.Content, nil
+}
+
+// responseStructured performs a single Responses API round trip with
+// text.format set to the command-options JSON schema and returns the raw
+// output text.
+func (c *AiClient) responseStructured(ctx context.Context, messages []Message, model string) (string, error) {
+	var input ResponseInput
+	var instructions string
+
+	if len(messages) == 0 {
+		return "", fmt.Errorf("no messages provided")
+	}
+
+	if messages[0].Role == "system" {
+		instructions = messages[0].Content
+		if len(messages) > 1 {
+			input = messages[1:]
+		} else {
+			return "", fmt.Errorf("only system message provided, no user message to process")
+		}
+	} else {
+		input = messages
+	}
+
+	reqBody := ResponseRequest{
+		Model:        model,
+		Input:        input,
+		Instructions: instructions,
+		Store:        false,
+		Text: map[string]interface{}{
+			"format": map[string]interface{}{
+				"type":   "json_schema",
+				"name":   "command_options",
+				"schema": commandOptionsSchema(),
+			},
+		},
+	}
+
+	var apiKey string
+	var baseURL string
+
+	if c.configMgr != nil {
+		if modelConfig, exists := c.configMgr.GetCurrentModelConfig(); exists && modelConfig.Provider == "openai" {
+			apiKey = modelConfig.APIKey
+			baseURL = modelConfig.BaseURL
+		}
+	}
+	if apiKey == "" {
+		apiKey = c.config.OpenAI.APIKey
+	}
+	if baseURL == "" {
+		baseURL = c.config.OpenAI.BaseURL
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	url := baseURL + "/responses"
+
+	reqJSON, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqJSON))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("HTTP-Referer", "https://github.com/andreim2k/aiterm")
+	req.Header.Set("X-Title", "AITerm")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", parseAPIError(resp.StatusCode, body)
+	}
+
+	var response Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if response.Error != nil {
+		return "", fmt.Errorf("API error: %s", response.Error.Message)
+	}
+
+	if response.OutputText != "" {
+		return response.OutputText, nil
+	}
+
+	for _, item := range response.Output {
+		if item.Type == "message" && item.Status == "completed" {
+			for _, content := range item.Content {
+				if (content.Type == "output_text" || content.Type == "text") && content.Text != "" {
+					return content.Text, nil
+				}
+			}
+		}
+	}
+
+	logger.Debug("Structured Responses API call returned no output text")
+	return "", fmt.Errorf("no response content returned (model: %s, status: %d)", model, resp.StatusCode)
+}
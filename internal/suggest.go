@@ -0,0 +1,219 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/andreim2k/aiterm/internal/i18n"
+)
+
+// GetSuggestion returns a single best-guess ghost-text completion for the
+// remainder of prefix: the text a caller should display dim/gray after the
+// cursor, not the full command. It is the in-process entry point used by
+// the --ai-suggest flag and by the suggest daemon, driven by
+// cfg.Aish.AutosuggestStrategy ("history", "ai", or "hybrid"; hybrid is the
+// default). An empty result with a nil error means no confident completion
+// was found.
+func (m *Manager) GetSuggestion(ctx context.Context, prefix string) (string, error) {
+	cwd, _ := os.Getwd()
+	return m.getSuggestion(ctx, cwd, prefix)
+}
+
+// getSuggestion is GetSuggestion with an explicit cwd, so the suggest
+// daemon can serve a request on behalf of a shell sitting in a different
+// directory than the daemon's own.
+func (m *Manager) getSuggestion(ctx context.Context, cwd, prefix string) (string, error) {
+	prefix = strings.TrimRight(prefix, "\n")
+	if prefix == "" {
+		return "", nil
+	}
+
+	model := m.GetModel()
+	key := SuggestionKey{Cwd: cwd, Prefix: prefix, Model: model}
+	if m.Suggestions != nil {
+		if cached, ok := m.Suggestions.Get(key); ok {
+			return cached, nil
+		}
+	}
+
+	strategy := m.Config.Aish.AutosuggestStrategy
+	if strategy == "" {
+		strategy = "hybrid"
+	}
+
+	var suggestion string
+	var err error
+	switch strategy {
+	case "history":
+		suggestion, err = historySuggestion(prefix)
+	case "ai":
+		suggestion, err = m.aiSuggestion(ctx, cwd, prefix)
+	default: // "hybrid"
+		suggestion, err = historySuggestion(prefix)
+		if err == nil && suggestion == "" {
+			suggestion, err = m.aiSuggestion(ctx, cwd, prefix)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if m.Suggestions != nil {
+		m.Suggestions.Put(key, suggestion)
+	}
+	return suggestion, nil
+}
+
+// aiSuggestion asks the model to continue prefix, returning only the text
+// to append (never the prefix itself).
+func (m *Manager) aiSuggestion(ctx context.Context, cwd, prefix string) (string, error) {
+	systemPrompt := fmt.Sprintf(`%s
+
+Operating System: %s
+Current Directory: %s
+
+Rules:
+1. Respond with ONLY the characters to append after the user's input - never repeat the input itself.
+2. No explanations, no markdown, no quotes.
+3. If you have no confident completion, respond with an empty string.`, i18n.Get("prompt.autosuggest_system"), m.OS, cwd)
+
+	messages := []ChatMessage{
+		{Content: systemPrompt, FromUser: false},
+		{Content: prefix, FromUser: true},
+	}
+
+	response, err := m.AiClient.GetResponseFromChatMessages(ctx, messages, m.GetModel())
+	if err != nil {
+		return "", fmt.Errorf("failed to get AI suggestion: %w", err)
+	}
+
+	return strings.TrimRight(response, "\n"), nil
+}
+
+// historySuggestion implements the "history" strategy: zsh-autosuggestions'
+// match_prev_cmd style, returning the remainder of the most recent shell-
+// history line that starts with prefix, or "" if none does.
+func historySuggestion(prefix string) (string, error) {
+	histPath := historyFilePath()
+	if histPath == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(histPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read history file %s: %w", histPath, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := cleanHistoryLine(lines[i])
+		if line == "" || line == prefix {
+			continue
+		}
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimPrefix(line, prefix), nil
+		}
+	}
+
+	return "", nil
+}
+
+// cleanHistoryLine strips zsh's extended-history timestamp prefix
+// (": 1700000000:0;actual command") and fish's entry prefix
+// ("- cmd: actual command") so plain prefix matching works against zsh,
+// bash, and fish history files alike.
+func cleanHistoryLine(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, ": ") {
+		if idx := strings.Index(line, ";"); idx != -1 {
+			return line[idx+1:]
+		}
+	}
+	if strings.HasPrefix(line, "- cmd: ") {
+		return strings.TrimPrefix(line, "- cmd: ")
+	}
+	return line
+}
+
+// historyFilePath resolves the shell history file to scan for the
+// "history" autosuggest strategy: $HISTFILE if set, otherwise the default
+// path for the user's detected shell.
+func historyFilePath() string {
+	if hist := os.Getenv("HISTFILE"); hist != "" {
+		return hist
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	shell := os.Getenv("SHELL")
+	switch {
+	case strings.Contains(shell, "zsh"):
+		return filepath.Join(homeDir, ".zsh_history")
+	case strings.Contains(shell, "fish"):
+		return filepath.Join(homeDir, ".local", "share", "fish", "fish_history")
+	default:
+		return filepath.Join(homeDir, ".bash_history")
+	}
+}
+
+// RunAISuggest is the entry point for the --ai-suggest flag. It tries the
+// long-lived suggest daemon first (fast path once it's warm), falling back
+// to an in-process call to Manager.GetSuggestion when the daemon isn't
+// running yet, and opportunistically starts the daemon in the background
+// so later keystrokes hit the fast path instead of spawning a fresh aiterm
+// process (with its own AI-client and cache warmup) every time.
+func RunAISuggest(ctx context.Context, mgr *Manager, prefix string) (string, error) {
+	socketPath := DefaultSuggestSocketPath()
+	cwd, _ := os.Getwd()
+
+	if suggestion, err := RequestSuggestion(socketPath, cwd, prefix); err == nil {
+		return suggestion, nil
+	}
+
+	go spawnSuggestDaemon()
+
+	return mgr.GetSuggestion(ctx, prefix)
+}
+
+// spawnSuggestDaemon best-effort forks a detached `aiterm --ai-suggest-daemon`
+// so the next RunAISuggest call can hit the warm socket instead of falling
+// back to an in-process call again. RunAISuggest calls this once per missed
+// request, and misses cluster (every keystroke until the daemon comes up,
+// each as its own `aiterm --ai-suggest` process), so it first atomically
+// claims the daemon's pid lock with claimLock and gives up if another call
+// already holds it (starting or running) - without that atomicity, two
+// calls a few milliseconds apart could both see no live daemon, both fork
+// one, and race in SuggestDaemon.Serve, where one daemon's unlink-then-listen
+// can delete the unix socket another just bound.
+func spawnSuggestDaemon() {
+	lockPath := daemonLockPath(DefaultSuggestSocketPath())
+	if !claimLock(lockPath) {
+		return
+	}
+
+	aitermPath, err := os.Executable()
+	if err != nil {
+		aitermPath = os.Args[0]
+	}
+
+	cmd := exec.Command(aitermPath, "--ai-suggest-daemon")
+	cmd.Stdin = nil
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+	if err := cmd.Start(); err != nil {
+		os.Remove(lockPath)
+		return
+	}
+
+	_ = writeLockPid(lockPath, cmd.Process.Pid)
+}
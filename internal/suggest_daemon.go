@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// SuggestRequest is one line of the suggest daemon's request protocol: a
+// JSON object, terminated by \n, carrying the context needed to produce a
+// ghost-text completion.
+type SuggestRequest struct {
+	Cwd    string `json:"cwd"`
+	Prefix string `json:"prefix"`
+}
+
+// SuggestResponse is the daemon's reply to a SuggestRequest.
+type SuggestResponse struct {
+	Suggestion string `json:"suggestion"`
+	Error      string `json:"error,omitempty"`
+}
+
+// DefaultSuggestSocketPath returns the unix socket the suggest daemon
+// listens on, under $XDG_RUNTIME_DIR (falling back to os.TempDir()) so
+// each logged-in user gets their own socket without colliding on a shared
+// path.
+func DefaultSuggestSocketPath() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = os.TempDir()
+	}
+	return filepath.Join(runtimeDir, "aiterm-suggest.sock")
+}
+
+// SuggestDaemon is the long-lived process behind `aiterm --ai-suggest-daemon`.
+// It keeps one Manager (and therefore one warmed-up AI client and
+// SuggestionCache) alive across requests, so the ghost-text keybinding can
+// turn every keystroke into a cheap unix-socket round-trip instead of
+// paying process-startup and cache-miss cost each time.
+type SuggestDaemon struct {
+	mgr        *Manager
+	socketPath string
+	lockPath   string
+}
+
+// NewSuggestDaemon creates a daemon backed by mgr, listening on socketPath.
+func NewSuggestDaemon(mgr *Manager, socketPath string) *SuggestDaemon {
+	return &SuggestDaemon{mgr: mgr, socketPath: socketPath, lockPath: daemonLockPath(socketPath)}
+}
+
+// Serve listens on d.socketPath until ctx is cancelled, handling each
+// connection as one suggestion request. It claims d.lockPath for its own
+// pid before binding the socket, so spawnSuggestDaemon can tell a live
+// daemon apart from a crashed one that left its socket file behind.
+func (d *SuggestDaemon) Serve(ctx context.Context) error {
+	_ = writeLockPid(d.lockPath, os.Getpid())
+	defer os.Remove(d.lockPath)
+
+	_ = os.Remove(d.socketPath)
+
+	listener, err := net.Listen("unix", d.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", d.socketPath, err)
+	}
+	defer listener.Close()
+	defer os.Remove(d.socketPath)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				return fmt.Errorf("accept failed: %w", err)
+			}
+		}
+		go d.handleConn(ctx, conn)
+	}
+}
+
+func (d *SuggestDaemon) handleConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var req SuggestRequest
+		resp := SuggestResponse{}
+
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else if suggestion, err := d.mgr.getSuggestion(ctx, req.Cwd, req.Prefix); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Suggestion = suggestion
+		}
+
+		line, err := json.Marshal(resp)
+		if err != nil {
+			logger.Error("Failed to encode suggest response: %v", err)
+			continue
+		}
+		line = append(line, '\n')
+		if _, err := conn.Write(line); err != nil {
+			return
+		}
+	}
+}
+
+// RequestSuggestion is the one-shot client side of the protocol: it dials
+// socketPath, sends a single (cwd, prefix) request, and returns the
+// daemon's reply. It backs RunAISuggest's fast path.
+func RequestSuggestion(socketPath, cwd, prefix string) (string, error) {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to suggest daemon at %s: %w", socketPath, err)
+	}
+	defer conn.Close()
+
+	req, err := json.Marshal(SuggestRequest{Cwd: cwd, Prefix: prefix})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode suggestion request: %w", err)
+	}
+	req = append(req, '\n')
+	if _, err := conn.Write(req); err != nil {
+		return "", fmt.Errorf("failed to send suggestion request: %w", err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", fmt.Errorf("failed to read suggestion response: %w", err)
+		}
+		return "", fmt.Errorf("suggest daemon closed the connection without a response")
+	}
+
+	var resp SuggestResponse
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		return "", fmt.Errorf("failed to decode suggestion response: %w", err)
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("suggest daemon error: %s", resp.Error)
+	}
+
+	return resp.Suggestion, nil
+}
+
+// daemonLockPath returns the pid-lock file spawnSuggestDaemon and Serve use
+// to agree on whether a suggest daemon is already running for socketPath,
+// since the socket file alone can't distinguish a live daemon from a
+// crashed one that left it behind.
+func daemonLockPath(socketPath string) string {
+	return socketPath + ".lock"
+}
+
+// claimLock atomically claims lockPath for the calling process, returning
+// true only if the claim succeeded. It uses O_EXCL so that, unlike a
+// read-then-write check, two processes racing to claim the same lockPath at
+// the same instant can't both succeed: the OS guarantees exactly one of the
+// concurrent O_CREATE|O_EXCL opens wins. If the file already exists, it's
+// only treated as stale (and reclaimed) when the pid recorded in it is no
+// longer alive.
+func claimLock(lockPath string) bool {
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, writeErr := f.WriteString(strconv.Itoa(os.Getpid()))
+			closeErr := f.Close()
+			return writeErr == nil && closeErr == nil
+		}
+		if !os.IsExist(err) {
+			return false
+		}
+
+		pid, ok := readLockPid(lockPath)
+		if !ok || pidAlive(pid) {
+			return false
+		}
+		os.Remove(lockPath)
+	}
+	return false
+}
+
+// writeLockPid atomically replaces lockPath's contents with pid.
+func writeLockPid(lockPath string, pid int) error {
+	tmp := lockPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(pid)), 0o600); err != nil {
+		return fmt.Errorf("failed to write lock file %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, lockPath); err != nil {
+		return fmt.Errorf("failed to install lock file %s: %w", lockPath, err)
+	}
+	return nil
+}
+
+// readLockPid reads the pid recorded at lockPath, if any.
+func readLockPid(lockPath string) (int, bool) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, false
+	}
+	return pid, true
+}
+
+// pidAlive reports whether pid names a running process, by sending it the
+// null signal (which performs the existence check without actually
+// signaling the process).
+func pidAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
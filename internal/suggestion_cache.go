@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"container/list"
+	"sync"
+)
+
+// SuggestionKey identifies a cached ghost-text suggestion by the context it
+// was generated under: working directory, the exact buffer prefix typed so
+// far, and the model that generated it (switching models invalidates the
+// cache instead of returning a stale guess).
+type SuggestionKey struct {
+	Cwd    string
+	Prefix string
+	Model  string
+}
+
+type suggestionEntry struct {
+	key   SuggestionKey
+	value string
+}
+
+// SuggestionCache is a small LRU of SuggestionKey -> suggestion text, so
+// re-typing a prefix the user has already seen a completion for (e.g.
+// after backspacing and retyping) is instant instead of round-tripping to
+// the model or the suggest daemon again. It backs Manager.GetSuggestion.
+type SuggestionCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[SuggestionKey]*list.Element
+}
+
+// NewSuggestionCache creates a SuggestionCache holding at most capacity
+// entries.
+func NewSuggestionCache(capacity int) *SuggestionCache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &SuggestionCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[SuggestionKey]*list.Element),
+	}
+}
+
+// Get returns the cached suggestion for key, if any, marking it
+// most-recently-used.
+func (c *SuggestionCache) Get(key SuggestionKey) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	c.ll.MoveToFront(elem)
+	return elem.Value.(*suggestionEntry).value, true
+}
+
+// Put stores value for key, evicting the least-recently-used entry if the
+// cache is already at capacity.
+func (c *SuggestionCache) Put(key SuggestionKey, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*suggestionEntry).value = value
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	elem := c.ll.PushFront(&suggestionEntry{key: key, value: value})
+	c.items[key] = elem
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*suggestionEntry).key)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+import "testing"
+
+func TestSuggestionCacheGetPutRoundTrip(t *testing.T) {
+	cache := NewSuggestionCache(2)
+	key := SuggestionKey{Cwd: "/tmp", Prefix: "ls -", Model: "gpt-4"}
+
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	cache.Put(key, "la")
+	got, ok := cache.Get(key)
+	if !ok || got != "la" {
+		t.Fatalf("Get() = %q, %v, want %q, true", got, ok, "la")
+	}
+}
+
+func TestSuggestionCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewSuggestionCache(2)
+	a := SuggestionKey{Cwd: "/tmp", Prefix: "a", Model: "m"}
+	b := SuggestionKey{Cwd: "/tmp", Prefix: "b", Model: "m"}
+	c := SuggestionKey{Cwd: "/tmp", Prefix: "c", Model: "m"}
+
+	cache.Put(a, "A")
+	cache.Put(b, "B")
+	cache.Put(c, "C") // evicts a, the least recently used
+
+	if _, ok := cache.Get(a); ok {
+		t.Error("expected a to be evicted")
+	}
+	if _, ok := cache.Get(b); !ok {
+		t.Error("expected b to survive eviction")
+	}
+	if _, ok := cache.Get(c); !ok {
+		t.Error("expected c to survive eviction")
+	}
+}
+
+func TestSuggestionCacheGetRefreshesRecency(t *testing.T) {
+	cache := NewSuggestionCache(2)
+	a := SuggestionKey{Cwd: "/tmp", Prefix: "a", Model: "m"}
+	b := SuggestionKey{Cwd: "/tmp", Prefix: "b", Model: "m"}
+	c := SuggestionKey{Cwd: "/tmp", Prefix: "c", Model: "m"}
+
+	cache.Put(a, "A")
+	cache.Put(b, "B")
+	cache.Get(a)      // a is now more recently used than b
+	cache.Put(c, "C") // evicts b, not a
+
+	if _, ok := cache.Get(a); !ok {
+		t.Error("expected a to survive eviction after being refreshed")
+	}
+	if _, ok := cache.Get(b); ok {
+		t.Error("expected b to be evicted as the least recently used")
+	}
+}
+
+func TestSuggestionCachePutOverwritesExistingKey(t *testing.T) {
+	cache := NewSuggestionCache(2)
+	key := SuggestionKey{Cwd: "/tmp", Prefix: "g", Model: "m"}
+
+	cache.Put(key, "first")
+	cache.Put(key, "second")
+
+	got, ok := cache.Get(key)
+	if !ok || got != "second" {
+		t.Fatalf("Get() = %q, %v, want %q, true", got, ok, "second")
+	}
+}
+
+func TestNewSuggestionCacheNonPositiveCapacityDefaultsToOne(t *testing.T) {
+	cache := NewSuggestionCache(0)
+	a := SuggestionKey{Cwd: "/tmp", Prefix: "a", Model: "m"}
+	b := SuggestionKey{Cwd: "/tmp", Prefix: "b", Model: "m"}
+
+	cache.Put(a, "A")
+	cache.Put(b, "B")
+
+	if _, ok := cache.Get(a); ok {
+		t.Error("expected a to be evicted with capacity 1")
+	}
+	if _, ok := cache.Get(b); !ok {
+		t.Error("expected b to be retained with capacity 1")
+	}
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andreim2k/aiterm/internal/i18n"
+)
+
+// DefaultTranslateTimeout bounds how long a streamed translation waits for
+// the model before giving up; it is the default for the --timeout flag.
+const DefaultTranslateTimeout = 20 * time.Second
+
+// TranslateEvent is one incremental event emitted by
+// TranslateNaturalLanguageStream. Candidate is set once a line of the
+// model's streamed output completes and survives the same filtering
+// ParseCommandLines applies to a full response; Done marks the terminal
+// event of a stream that ends successfully. A stream that fails ends with
+// an event carrying Err instead, with no Done event after it.
+type TranslateEvent struct {
+	Candidate string
+	Done      bool
+	Err       error
+}
+
+// TranslateNaturalLanguageStream behaves like
+// mgr.AiClient.TranslateNaturalLanguageMultiple, but streams up to count
+// candidates to the returned channel as they complete instead of blocking
+// until the whole response is buffered, so a caller like
+// RunAITranslateMultiple can render them progressively. Cancelling ctx (the
+// shell wrapper forwards Esc as SIGINT to the --ai-translate-multiple child,
+// which cancels ctx here) stops the underlying HTTP request and closes the
+// channel without a final Done event. Each candidate is run through the
+// user's RiskPolicy (AnnotateConfirmPrefix) before being emitted, so the
+// shell wrappers can require an explicit y/N on destructive commands.
+func TranslateNaturalLanguageStream(ctx context.Context, mgr *Manager, naturalLanguage string, count int) (<-chan TranslateEvent, error) {
+	policy, err := LoadUserRiskPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	shellPath := os.Getenv("SHELL")
+	if shellPath == "" {
+		shellPath = "/bin/bash"
+	}
+	cwd, _ := os.Getwd()
+
+	systemPrompt := fmt.Sprintf(`%s Output ONLY the %d most commonly used Linux shell commands for the given task, one per line, most common first. No explanations, no numbering, no markdown.
+
+Operating System: %s
+Shell: %s
+Current Directory: %s`, i18n.Get("prompt.shell_generator"), count, mgr.OS, shellPath, cwd)
+
+	messages := []ChatMessage{
+		{Content: systemPrompt, FromUser: false},
+		{Content: fmt.Sprintf("Task: %s", naturalLanguage), FromUser: true},
+	}
+
+	events := make(chan TranslateEvent)
+
+	go func() {
+		defer close(events)
+
+		var buf strings.Builder
+		sent := 0
+
+		emit := func(raw string) {
+			for _, line := range ParseCommandLines(raw) {
+				if sent >= count {
+					return
+				}
+				events <- TranslateEvent{Candidate: AnnotateConfirmPrefix(policy, line)}
+				sent++
+			}
+		}
+
+		err := mgr.AiClient.GetResponseFromChatMessagesStream(ctx, messages, mgr.GetModel(), func(chunk Chunk) {
+			buf.WriteString(chunk.Delta)
+			for sent < count {
+				s := buf.String()
+				idx := strings.IndexByte(s, '\n')
+				if idx < 0 {
+					break
+				}
+				line := s[:idx]
+				buf.Reset()
+				buf.WriteString(s[idx+1:])
+				emit(line)
+			}
+		})
+		if err != nil {
+			events <- TranslateEvent{Err: err}
+			return
+		}
+
+		if sent < count {
+			emit(buf.String())
+		}
+		events <- TranslateEvent{Done: true}
+	}()
+
+	return events, nil
+}
+
+// RunAITranslateMultiple is the entry point for the --ai-translate-multiple
+// flag. It streams up to count candidate commands for naturalLanguage to w,
+// one per line, flushing after each so the shell wrapper can render them as
+// they arrive instead of polling a background job to completion. Candidates
+// classified as destructive by the user's RiskPolicy carry a "[confirm] "
+// prefix (see AnnotateConfirmPrefix); the zsh/bash/fish wrappers strip it
+// and require an explicit y/N before accepting such a command into the
+// buffer. Cancelling ctx (Esc forwarded as SIGINT to this process) ends the
+// stream early and returns nil rather than an error, since the caller is no
+// longer waiting for a result.
+func RunAITranslateMultiple(ctx context.Context, mgr *Manager, naturalLanguage string, count int, w io.Writer) error {
+	events, err := TranslateNaturalLanguageStream(ctx, mgr, naturalLanguage, count)
+	if err != nil {
+		return err
+	}
+
+	for event := range events {
+		if event.Err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return event.Err
+		}
+		if event.Done {
+			return nil
+		}
+		if _, err := fmt.Fprintln(w, event.Candidate); err != nil {
+			return err
+		}
+		if f, ok := w.(*os.File); ok {
+			_ = f.Sync()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,404 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/andreim2k/aiterm/config"
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// Usage holds token counts for a single completion, independent of which
+// provider's response shape they were parsed from.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	ReasoningTokens  int `json:"reasoning_tokens,omitempty"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// OpenAIUsage is the `usage` block returned by chat-completions-compatible
+// providers.
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// AsUsage converts an OpenAIUsage into the provider-agnostic Usage shape.
+func (u OpenAIUsage) AsUsage() Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
+// CompletionResult wraps a completion's text together with the token usage
+// and model that produced it, so callers that need cost accounting don't
+// have to re-derive it from the raw response.
+type CompletionResult struct {
+	Text  string
+	Usage Usage
+	Model string
+}
+
+// UsageEntry is a single JSONL record persisted by UsageTracker.
+type UsageEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	Usage     Usage     `json:"usage"`
+	CostUSD   float64   `json:"cost_usd"`
+}
+
+// PriceEntry is a single model's $/1K-tokens pricing, loaded from a YAML
+// price table.
+type PriceEntry struct {
+	Model                string  `yaml:"model"`
+	PromptPricePer1K     float64 `yaml:"prompt_price_per_1k"`
+	CompletionPricePer1K float64 `yaml:"completion_price_per_1k"`
+}
+
+// UsageTracker accumulates prompt/completion/reasoning token usage per
+// session, persists it to a JSONL log under the config dir, and applies a
+// per-model price table to compute running cost.
+type UsageTracker struct {
+	logPath string
+	prices  map[string]PriceEntry
+}
+
+// NewUsageTracker opens the usage log and price table under the config dir.
+// A missing price table is not an error; costs simply report as zero for
+// unpriced models.
+func NewUsageTracker() (*UsageTracker, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config dir: %w", err)
+	}
+
+	prices, err := loadPriceTable(filepath.Join(configDir, "prices.yaml"))
+	if err != nil {
+		logger.Debug("Failed to load usage price table: %v", err)
+		prices = map[string]PriceEntry{}
+	}
+
+	return &UsageTracker{
+		logPath: filepath.Join(configDir, "usage.jsonl"),
+		prices:  prices,
+	}, nil
+}
+
+func loadPriceTable(path string) (map[string]PriceEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []PriceEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse price table %s: %w", path, err)
+	}
+
+	table := make(map[string]PriceEntry, len(entries))
+	for _, entry := range entries {
+		table[entry.Model] = entry
+	}
+	return table, nil
+}
+
+func (t *UsageTracker) cost(model string, usage Usage) float64 {
+	price, exists := t.prices[model]
+	if !exists {
+		return 0
+	}
+	return float64(usage.PromptTokens)/1000*price.PromptPricePer1K +
+		float64(usage.CompletionTokens)/1000*price.CompletionPricePer1K
+}
+
+// Record appends a usage entry to the JSONL log.
+func (t *UsageTracker) Record(provider, model string, usage Usage) error {
+	entry := UsageEntry{
+		Timestamp: time.Now(),
+		Provider:  provider,
+		Model:     model,
+		Usage:     usage,
+		CostUSD:   t.cost(model, usage),
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage entry: %w", err)
+	}
+
+	f, err := os.OpenFile(t.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage entry: %w", err)
+	}
+
+	return nil
+}
+
+// UsageSummary aggregates usage and cost for a single provider/model pair
+// over a reporting period.
+type UsageSummary struct {
+	Provider string
+	Model    string
+	Usage    Usage
+	CostUSD  float64
+}
+
+// Summary reads the usage log and returns per-provider/model totals for
+// every entry recorded at or after since.
+func (t *UsageTracker) Summary(since time.Time) ([]UsageSummary, error) {
+	f, err := os.Open(t.logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	totals := make(map[string]*UsageSummary)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry UsageEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Timestamp.Before(since) {
+			continue
+		}
+
+		key := entry.Provider + "/" + entry.Model
+		summary, exists := totals[key]
+		if !exists {
+			summary = &UsageSummary{Provider: entry.Provider, Model: entry.Model}
+			totals[key] = summary
+		}
+
+		summary.Usage.PromptTokens += entry.Usage.PromptTokens
+		summary.Usage.CompletionTokens += entry.Usage.CompletionTokens
+		summary.Usage.ReasoningTokens += entry.Usage.ReasoningTokens
+		summary.Usage.TotalTokens += entry.Usage.TotalTokens
+		summary.CostUSD += entry.CostUSD
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+
+	summaries := make([]UsageSummary, 0, len(totals))
+	for _, summary := range totals {
+		summaries = append(summaries, *summary)
+	}
+	return summaries, nil
+}
+
+// ChatCompletionWithUsage behaves like AiClient.ChatCompletion but returns
+// the token usage alongside the text, so callers can feed it to a
+// UsageTracker for cost accounting.
+func (c *AiClient) ChatCompletionWithUsage(ctx context.Context, messages []Message, model string) (CompletionResult, error) {
+	completionResp, err := c.chatCompletionFull(ctx, messages, model, nil)
+	if err != nil {
+		return CompletionResult{}, err
+	}
+
+	return CompletionResult{
+		Text:  completionResp.Choices[0].Message.Content,
+		Usage: completionResp.Usage.AsUsage(),
+		Model: model,
+	}, nil
+}
+
+// ResponseWithUsage behaves like AiClient.Response but returns the token
+// usage alongside the text, so callers can feed it to a UsageTracker for
+// cost accounting.
+func (c *AiClient) ResponseWithUsage(ctx context.Context, messages []Message, model string) (CompletionResult, error) {
+	response, err := c.responseFull(ctx, messages, model)
+	if err != nil {
+		return CompletionResult{}, err
+	}
+
+	text := response.OutputText
+	if text == "" {
+		for _, item := range response.Output {
+			if item.Type != "message" || item.Status != "completed" {
+				continue
+			}
+			for _, content := range item.Content {
+				if (content.Type == "output_text" || content.Type == "text") && content.Text != "" {
+					text = content.Text
+				}
+			}
+		}
+	}
+	if text == "" {
+		return CompletionResult{}, fmt.Errorf("no response content returned (model: %s)", model)
+	}
+
+	usage := Usage{}
+	if response.Usage != nil {
+		usage = Usage{
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			ReasoningTokens:  response.Usage.ReasoningTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+		}
+	}
+
+	return CompletionResult{Text: text, Usage: usage, Model: model}, nil
+}
+
+// responseFull performs a single Responses API round trip and returns the
+// full decoded response, including usage, mirroring AiClient.Response.
+func (c *AiClient) responseFull(ctx context.Context, messages []Message, model string) (*Response, error) {
+	var input ResponseInput
+	var instructions string
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages provided")
+	}
+
+	if messages[0].Role == "system" {
+		instructions = messages[0].Content
+		if len(messages) > 1 {
+			input = messages[1:]
+		} else {
+			return nil, fmt.Errorf("only system message provided, no user message to process")
+		}
+	} else {
+		input = messages
+	}
+
+	reqBody := ResponseRequest{
+		Model:        model,
+		Input:        input,
+		Instructions: instructions,
+		Store:        false,
+	}
+
+	var apiKey string
+	var baseURL string
+
+	if c.backends != nil {
+		if backend, exists := c.backends.GetByName(model); exists && backend.Provider == "openai" {
+			apiKey = backend.APIKey()
+			baseURL = backend.BaseURL
+			backend.Parameters.ApplyToResponseRequest(&reqBody)
+		}
+	}
+
+	if apiKey == "" && c.configMgr != nil {
+		if modelConfig, exists := c.configMgr.GetCurrentModelConfig(); exists && modelConfig.Provider == "openai" {
+			apiKey = modelConfig.APIKey
+			baseURL = modelConfig.BaseURL
+		}
+	}
+	if apiKey == "" {
+		apiKey = c.config.OpenAI.APIKey
+	}
+	if baseURL == "" {
+		baseURL = c.config.OpenAI.BaseURL
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	url := baseURL + "/responses"
+
+	reqJSON, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("HTTP-Referer", "https://github.com/andreim2k/aiterm")
+	req.Header.Set("X-Title", "AITerm")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp.StatusCode, body)
+	}
+
+	var response Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("API error: %s", response.Error.Message)
+	}
+
+	return &response, nil
+}
+
+// PrintUsageReport prints today/week/month usage totals broken down by
+// provider and model. It is the entry point for the `aiterm usage`
+// subcommand.
+func PrintUsageReport(tracker *UsageTracker) error {
+	now := time.Now()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	periods := []struct {
+		label string
+		since time.Time
+	}{
+		{"Today", todayStart},
+		{"This week", now.AddDate(0, 0, -7)},
+		{"This month", now.AddDate(0, -1, 0)},
+	}
+
+	for _, period := range periods {
+		summaries, err := tracker.Summary(period.since)
+		if err != nil {
+			return fmt.Errorf("failed to summarize usage for %s: %w", period.label, err)
+		}
+
+		fmt.Printf("%s:\n", period.label)
+		if len(summaries) == 0 {
+			fmt.Println("  (no usage recorded)")
+			continue
+		}
+
+		for _, summary := range summaries {
+			fmt.Printf("  %s/%s: %d prompt + %d completion tokens, $%.4f\n",
+				summary.Provider, summary.Model,
+				summary.Usage.PromptTokens, summary.Usage.CompletionTokens, summary.CostUSD)
+		}
+	}
+
+	return nil
+}
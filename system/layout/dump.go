@@ -0,0 +1,131 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/andreim2k/aiterm/system"
+)
+
+// DumpSession is the inverse of Apply: it walks sessionTarget's current
+// windows and panes and produces the Config that would recreate them,
+// so a user can iterate on a layout interactively in tmux and then
+// persist it to a file (or share it to another machine) instead of
+// hand-writing the YAML.
+func DumpSession(sessionTarget string) (*Config, error) {
+	windows, err := system.TmuxWindowsInfo(sessionTarget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list windows for session %s: %w", sessionTarget, err)
+	}
+
+	cfg := &Config{Session: sessionTarget}
+
+	for _, w := range windows {
+		win, err := dumpWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Windows = append(cfg.Windows, win)
+	}
+
+	return cfg, nil
+}
+
+// dumpWindow captures one window's panes, in tmux's natural top-to-bottom,
+// left-to-right order, recovering each pane's split direction and size
+// percentage from its rectangle relative to the previous pane.
+func dumpWindow(w system.TmuxWindowInfo) (Window, error) {
+	rects, err := system.TmuxPanesRects(w.Id)
+	if err != nil {
+		return Window{}, fmt.Errorf("failed to list panes for window %s: %w", w.Id, err)
+	}
+
+	// w.Layout is tmux's raw window-layout checksum string, not one of the
+	// named presets Config.Window.Layout expects; there's no reliable way
+	// to map an arbitrary arrangement back to "tiled"/"main-horizontal"/etc,
+	// so it's left unset and Apply will reproduce the split tree exactly
+	// from the recovered panes instead.
+	win := Window{Name: w.Name}
+
+	var prev *system.TmuxPaneRect
+	for i := range rects {
+		rect := rects[i]
+
+		pane := Pane{}
+		if !isLoginShellCommand(rect.Command) {
+			pane.Command = rect.Command
+		}
+
+		if path, err := system.TmuxPaneCurrentPath(rect.Id); err == nil {
+			pane.Root = path
+		}
+		if title, err := system.TmuxPaneTitle(rect.Id); err == nil && title != "" {
+			pane.Title = title
+		}
+
+		if prev != nil {
+			pane.Split, pane.Size = splitFrom(*prev, rect, w.Width, w.Height)
+		}
+
+		win.Panes = append(win.Panes, pane)
+		prev = &rect
+	}
+
+	win.Root = commonRoot(win.Panes)
+	return win, nil
+}
+
+// loginShellCommands are tmux's #{pane_current_command} values for a pane
+// that's simply sitting at an interactive prompt, not running anything the
+// user explicitly started - the common case for an idle pane. Apply would
+// otherwise type this back in as the pane's Command, spawning a spurious
+// nested shell instead of reproducing "what the user has on-screen".
+var loginShellCommands = map[string]bool{
+	"bash": true, "zsh": true, "fish": true, "sh": true,
+	"dash": true, "ksh": true, "tcsh": true, "csh": true,
+}
+
+// isLoginShellCommand reports whether cmd is just the name of an
+// interactive shell (see loginShellCommands) rather than a real foreground
+// command the user started.
+func isLoginShellCommand(cmd string) bool {
+	return loginShellCommands[cmd]
+}
+
+// splitFrom infers the split direction and size percentage that produced
+// rect given the pane it was split from (prev) and the window's overall
+// dimensions.
+func splitFrom(prev, rect system.TmuxPaneRect, windowWidth, windowHeight int) (direction string, percent int) {
+	if rect.Left != prev.Left {
+		if windowWidth > 0 {
+			percent = rect.Width * 100 / windowWidth
+		}
+		return "h", percent
+	}
+
+	if windowHeight > 0 {
+		percent = rect.Height * 100 / windowHeight
+	}
+	return "v", percent
+}
+
+// commonRoot returns the directory shared by every pane's Root, so it can
+// be hoisted onto the window and dropped from panes, matching how a
+// hand-written layout file is typically laid out. It returns "" if the
+// panes disagree.
+func commonRoot(panes []Pane) string {
+	if len(panes) == 0 {
+		return ""
+	}
+
+	root := panes[0].Root
+	for _, p := range panes[1:] {
+		if p.Root != root {
+			return ""
+		}
+	}
+
+	for i := range panes {
+		panes[i].Root = ""
+	}
+	return root
+}
@@ -0,0 +1,93 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/andreim2k/aiterm/system"
+)
+
+func TestSplitFromVerticalStack(t *testing.T) {
+	prev := system.TmuxPaneRect{Top: 0, Left: 0, Width: 80, Height: 12}
+	rect := system.TmuxPaneRect{Top: 12, Left: 0, Width: 80, Height: 12}
+
+	direction, percent := splitFrom(prev, rect, 80, 24)
+	if direction != "v" {
+		t.Errorf("direction = %q, want %q", direction, "v")
+	}
+	if percent != 50 {
+		t.Errorf("percent = %d, want %d", percent, 50)
+	}
+}
+
+func TestSplitFromHorizontalSideBySide(t *testing.T) {
+	prev := system.TmuxPaneRect{Top: 0, Left: 0, Width: 40, Height: 24}
+	rect := system.TmuxPaneRect{Top: 0, Left: 41, Width: 39, Height: 24}
+
+	direction, percent := splitFrom(prev, rect, 80, 24)
+	if direction != "h" {
+		t.Errorf("direction = %q, want %q", direction, "h")
+	}
+	if percent != 48 {
+		t.Errorf("percent = %d, want %d", percent, 48)
+	}
+}
+
+func TestSplitFromZeroWindowDimensionYieldsZeroPercent(t *testing.T) {
+	prev := system.TmuxPaneRect{Top: 0, Left: 0, Width: 0, Height: 0}
+	rect := system.TmuxPaneRect{Top: 0, Left: 10, Width: 10, Height: 10}
+
+	direction, percent := splitFrom(prev, rect, 0, 0)
+	if direction != "h" {
+		t.Errorf("direction = %q, want %q", direction, "h")
+	}
+	if percent != 0 {
+		t.Errorf("percent = %d, want %d", percent, 0)
+	}
+}
+
+func TestCommonRootHoistsSharedRoot(t *testing.T) {
+	panes := []Pane{{Root: "/home/user/project"}, {Root: "/home/user/project"}}
+
+	root := commonRoot(panes)
+	if root != "/home/user/project" {
+		t.Errorf("commonRoot = %q, want %q", root, "/home/user/project")
+	}
+	for i, p := range panes {
+		if p.Root != "" {
+			t.Errorf("panes[%d].Root = %q, want empty after hoisting", i, p.Root)
+		}
+	}
+}
+
+func TestCommonRootReturnsEmptyWhenPanesDisagree(t *testing.T) {
+	panes := []Pane{{Root: "/home/user/a"}, {Root: "/home/user/b"}}
+
+	root := commonRoot(panes)
+	if root != "" {
+		t.Errorf("commonRoot = %q, want empty", root)
+	}
+	if panes[0].Root != "/home/user/a" || panes[1].Root != "/home/user/b" {
+		t.Errorf("panes should be left untouched when roots disagree, got %+v", panes)
+	}
+}
+
+func TestCommonRootEmptyPanes(t *testing.T) {
+	if root := commonRoot(nil); root != "" {
+		t.Errorf("commonRoot(nil) = %q, want empty", root)
+	}
+}
+
+func TestIsLoginShellCommand(t *testing.T) {
+	cases := map[string]bool{
+		"bash": true,
+		"zsh":  true,
+		"fish": true,
+		"vim":  false,
+		"":     false,
+	}
+	for cmd, want := range cases {
+		if got := isLoginShellCommand(cmd); got != want {
+			t.Errorf("isLoginShellCommand(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
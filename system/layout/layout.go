@@ -0,0 +1,271 @@
+// Package layout materializes a declarative session description - a
+// session name, its windows, and each window's panes - into a live tmux
+// session, built from the same primitives (system.TmuxCreateSession,
+// system.TmuxCreateNewPane, system.TmuxSetPaneTitle, ...) the rest of
+// aiterm uses to drive tmux. It lets a user define a richer multi-pane
+// workspace (e.g. chat + exec + logs + tests) in a YAML file instead of
+// hardcoding it in Go.
+package layout
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/andreim2k/aiterm/logger"
+	"github.com/andreim2k/aiterm/system"
+)
+
+// Config describes a single tmux session: its name and the windows to
+// create inside it.
+type Config struct {
+	Session string   `yaml:"session"`
+	Windows []Window `yaml:"windows"`
+}
+
+// Window describes one tmux window: its name, a root directory panes
+// inherit unless they set their own, and its panes. Layout is a tmux
+// layout preset (e.g. "even-vertical", "tiled", "main-horizontal")
+// applied once every pane has been created; an empty value leaves
+// whatever layout tmux's splits produced.
+type Window struct {
+	Name        string   `yaml:"name"`
+	Root        string   `yaml:"root,omitempty"`
+	Layout      string   `yaml:"layout,omitempty"`
+	BeforeStart []string `yaml:"before_start,omitempty"`
+	SyncPanes   bool     `yaml:"sync_panes,omitempty"`
+	Panes       []Pane   `yaml:"panes"`
+}
+
+// Pane describes one tmux pane. Split is the direction used to carve it
+// out of the previous pane ("v" for a vertical split stacking panes
+// top/bottom, "h" for horizontal placing them side by side); the first
+// pane in a window has no split and simply occupies the whole window.
+// Size is the new pane's size as a percentage of the pane it's split
+// from; 0 leaves tmux's default split in place.
+type Pane struct {
+	Root    string `yaml:"root,omitempty"`
+	Split   string `yaml:"split,omitempty"`
+	Size    int    `yaml:"size,omitempty"`
+	Command string `yaml:"command,omitempty"`
+	Title   string `yaml:"title,omitempty"`
+}
+
+// Load reads and parses a Config from a YAML file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layout config %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse layout config %s: %w", path, err)
+	}
+
+	if cfg.Session == "" {
+		return nil, fmt.Errorf("layout config %s has no session name", path)
+	}
+
+	return &cfg, nil
+}
+
+// Save writes cfg to path as YAML, the counterpart to Load used to persist
+// the Config that DumpSession captures from a live session.
+func Save(path string, cfg *Config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal layout config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write layout config %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Apply materializes cfg as a live tmux session. If a session named
+// cfg.Session already exists, Apply is idempotent: it reuses that session
+// and adds, as new windows, only the windows that aren't already present
+// (matched by name), rather than erroring or duplicating them. It returns a
+// tmux target for the first window it created (the session name when that
+// window is a new session's first window, otherwise the new window's id),
+// or cfg.Session when every window was already present, and selects it as
+// a final select-window/select-pane bootstrap step.
+func Apply(cfg *Config) (string, error) {
+	existing := system.TmuxHasSession(cfg.Session)
+	if !existing {
+		if _, err := system.TmuxCreateSession(); err != nil {
+			return "", fmt.Errorf("failed to create session for layout %s: %w", cfg.Session, err)
+		}
+		if err := system.TmuxRenameSession(cfg.Session); err != nil {
+			return "", fmt.Errorf("failed to name layout session %s: %w", cfg.Session, err)
+		}
+	}
+
+	existingWindows := map[string]bool{}
+	if existing {
+		names, err := system.TmuxWindowNames(cfg.Session)
+		if err != nil {
+			return "", fmt.Errorf("failed to list windows in session %s: %w", cfg.Session, err)
+		}
+		for _, n := range names {
+			existingWindows[n] = true
+		}
+	}
+
+	var bootstrapTarget string
+	// Only a freshly created session has a first window to reuse; windows
+	// added to an existing session are always opened as new windows.
+	firstWindow := !existing
+
+	for _, win := range cfg.Windows {
+		if existingWindows[win.Name] {
+			logger.Debug("Window %q already present in session %s, skipping", win.Name, cfg.Session)
+			continue
+		}
+
+		target, err := applyWindow(cfg.Session, win, firstWindow)
+		if err != nil {
+			return "", err
+		}
+		firstWindow = false
+
+		if bootstrapTarget == "" {
+			bootstrapTarget = target
+		}
+	}
+
+	if bootstrapTarget == "" {
+		bootstrapTarget = cfg.Session
+	}
+
+	if err := system.TmuxSelectWindow(bootstrapTarget); err != nil {
+		return "", fmt.Errorf("failed to select bootstrap window for layout %s: %w", cfg.Session, err)
+	}
+	if err := system.TmuxSelectPane(bootstrapTarget); err != nil {
+		logger.Debug("Failed to select bootstrap pane %s: %v", bootstrapTarget, err)
+	}
+
+	return bootstrapTarget, nil
+}
+
+// applyWindow creates win's panes and returns a tmux target for the window:
+// the session name when isFirstWindow, otherwise the new window's id. The
+// session's first window already exists as pane 0 of the freshly created
+// session, so it's reused instead of opening a new one.
+func applyWindow(session string, win Window, isFirstWindow bool) (string, error) {
+	var windowTarget string
+
+	if isFirstWindow {
+		windowTarget = session
+		if win.Name != "" {
+			if err := system.TmuxRenameWindow(windowTarget, win.Name); err != nil {
+				return "", fmt.Errorf("failed to name window %q: %w", win.Name, err)
+			}
+		}
+	} else {
+		id, err := system.TmuxNewWindow(session, win.Name)
+		if err != nil {
+			return "", fmt.Errorf("failed to create window %q: %w", win.Name, err)
+		}
+		windowTarget = id
+	}
+
+	panes := win.Panes
+	if len(panes) == 0 {
+		panes = []Pane{{}}
+	}
+
+	firstPaneId := windowTarget
+	currentPaneId := windowTarget
+
+	for i, pane := range panes {
+		var paneId string
+		var err error
+
+		switch {
+		case i == 0:
+			paneId = currentPaneId
+		case pane.Split == "h":
+			paneId, err = system.TmuxSplitPane(currentPaneId, "h", pane.Size)
+		default:
+			paneId, err = system.TmuxSplitPane(currentPaneId, "v", pane.Size)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to split pane %d of window %q: %w", i, win.Name, err)
+		}
+
+		if i == 0 {
+			firstPaneId = paneId
+		}
+		currentPaneId = paneId
+
+		root := pane.Root
+		if root == "" {
+			root = win.Root
+		}
+		if root != "" {
+			if err := system.TmuxSendKeys(paneId, "cd "+shellQuote(root), system.SendKeysOptions{}); err != nil {
+				return "", fmt.Errorf("failed to cd pane %d of window %q into %s: %w", i, win.Name, root, err)
+			}
+		}
+
+		if pane.Title != "" {
+			if err := system.TmuxSetPaneTitle(paneId, pane.Title); err != nil {
+				return "", fmt.Errorf("failed to title pane %d of window %q: %w", i, win.Name, err)
+			}
+		}
+
+		for _, hook := range win.BeforeStart {
+			if err := system.TmuxSendKeys(paneId, hook, system.SendKeysOptions{}); err != nil {
+				return "", fmt.Errorf("failed to run before_start hook in pane %d of window %q: %w", i, win.Name, err)
+			}
+		}
+
+		if pane.Command != "" {
+			if err := system.TmuxSendKeys(paneId, pane.Command, system.SendKeysOptions{}); err != nil {
+				return "", fmt.Errorf("failed to run command in pane %d of window %q: %w", i, win.Name, err)
+			}
+		}
+	}
+
+	if win.Layout != "" {
+		if err := system.TmuxSelectLayout(currentPaneId, win.Layout); err != nil {
+			return "", fmt.Errorf("failed to apply layout %q to window %q: %w", win.Layout, win.Name, err)
+		}
+	}
+
+	if win.SyncPanes {
+		if err := system.TmuxSetSyncPanes(windowTarget, true); err != nil {
+			return "", fmt.Errorf("failed to enable synchronize-panes for window %q: %w", win.Name, err)
+		}
+	}
+
+	return firstPaneId, nil
+}
+
+// shellQuote wraps a plain path in POSIX single quotes so it survives as one
+// shell word when concatenated into a "cd "+... command, even if it contains
+// spaces or other shell metacharacters (e.g. "~/My Documents"). Unlike
+// pane.Command/win.BeforeStart, root is documented as a plain path rather
+// than raw shell, so it gets quoted instead of passed through verbatim. A
+// leading "~/" is left unquoted so the shell still expands it to $HOME.
+func shellQuote(path string) string {
+	if path == "~" {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return "~/" + shellQuoteWord(rest)
+	}
+	return shellQuoteWord(path)
+}
+
+// shellQuoteWord wraps s in POSIX single quotes, escaping any embedded
+// single quotes so the result is always exactly one shell word.
+func shellQuoteWord(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,134 @@
+package system
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// DefaultRunnerResetKeys is sent after the interrupt (C-c) a Runner.Interrupt
+// call issues, to back out of a pager/REPL prompt left behind (e.g. less's
+// "q" and readline's C-u to clear whatever was typed) before the next
+// command runs.
+var DefaultRunnerResetKeys = []string{"q", "C-u"}
+
+// Runner wraps a single tmux pane designated as an execution target -
+// vimux's "runner pane" - giving the LLM a clean, testable place to run
+// commands separate from the chat pane. It's deliberately thin: every
+// method delegates to the package's Tmux* primitives, so a
+// caller can mock Runner.PaneId's backing pane in tests without faking
+// tmux itself.
+type Runner struct {
+	PaneId string
+	// ResetKeys overrides DefaultRunnerResetKeys for this Runner's
+	// Interrupt calls.
+	ResetKeys []string
+
+	owns bool
+}
+
+// NewRunner splits a new pane off windowTarget for the runner and returns
+// a Runner that owns it, so Close kills it.
+func NewRunner(windowTarget string) (*Runner, error) {
+	paneId, err := TmuxSplitPane(windowTarget, "v", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner pane in %s: %w", windowTarget, err)
+	}
+
+	return &Runner{PaneId: paneId, owns: true}, nil
+}
+
+// AttachRunner wraps an already-existing pane as a Runner that doesn't own
+// it, so Close leaves it running.
+func AttachRunner(paneId string) *Runner {
+	return &Runner{PaneId: paneId, owns: false}
+}
+
+// FindOrCreateRunner implements the "nearest existing pane" mode: it reuses
+// a pane in windowTarget already titled title instead of always splitting a
+// new one, so repeated calls (e.g. across chat turns) converge on the same
+// runner pane rather than accumulating a new split each time. If no pane
+// matches, it creates one via NewRunner and titles it.
+func FindOrCreateRunner(windowTarget, title string) (*Runner, error) {
+	if title != "" {
+		if paneId, err := TmuxFindPaneByTitle(windowTarget, title); err == nil && paneId != "" {
+			return AttachRunner(paneId), nil
+		}
+	}
+
+	runner, err := NewRunner(windowTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	if title != "" {
+		if err := TmuxSetPaneTitle(runner.PaneId, title); err != nil {
+			return nil, fmt.Errorf("failed to title runner pane %s: %w", runner.PaneId, err)
+		}
+	}
+
+	return runner, nil
+}
+
+// RunCommand types command into the runner pane followed by Enter. It
+// returns as soon as the keys are delivered; the pane may still be
+// running the command, or blocked on a prompt, when this returns.
+func (r *Runner) RunCommand(command string) error {
+	return TmuxSendKeys(r.PaneId, command, SendKeysOptions{})
+}
+
+// RunCommandAndWait types command into the runner pane followed by Enter,
+// then polls the pane until its current command or output tail matches
+// expect or timeout elapses. It returns a *SendKeysTimeoutError on
+// timeout, so a caller can tell a genuinely blocked command (password
+// prompt, editor) apart from one that simply finished quickly.
+func (r *Runner) RunCommandAndWait(command string, expect *regexp.Regexp, timeout time.Duration) error {
+	return TmuxSendKeys(r.PaneId, command, SendKeysOptions{Timeout: timeout, ExpectRegexp: expect})
+}
+
+// SendKeys sends keys to the runner pane verbatim (no Enter appended), for
+// tmux key names like "C-c" or arrow keys.
+func (r *Runner) SendKeys(keys string) error {
+	return TmuxSendRawKeys(r.PaneId, keys)
+}
+
+// Interrupt sends C-c to the runner pane followed by its reset sequence
+// (ResetKeys if set, otherwise DefaultRunnerResetKeys), to back out of
+// whatever was running and leave the pane at a clean prompt.
+func (r *Runner) Interrupt() error {
+	if err := TmuxSendRawKeys(r.PaneId, "C-c"); err != nil {
+		return err
+	}
+
+	reset := r.ResetKeys
+	if reset == nil {
+		reset = DefaultRunnerResetKeys
+	}
+	return TmuxSendRawKeys(r.PaneId, reset...)
+}
+
+// Clear wipes the runner pane's scrollback and visible screen.
+func (r *Runner) Clear() error {
+	return TmuxClearPane(r.PaneId)
+}
+
+// ZoomToggle toggles the runner pane between its normal size and filling
+// the whole window.
+func (r *Runner) ZoomToggle() error {
+	return TmuxToggleZoom(r.PaneId)
+}
+
+// LastOutput returns the last nLines lines of the runner pane's content.
+func (r *Runner) LastOutput(nLines int) (string, error) {
+	return TmuxCapturePane(r.PaneId, nLines)
+}
+
+// Close kills the runner pane if this Runner created it (NewRunner/
+// FindOrCreateRunner's create path); a Runner wrapping a pre-existing pane
+// via AttachRunner or FindOrCreateRunner's reuse path leaves it running.
+func (r *Runner) Close() error {
+	if !r.owns {
+		return nil
+	}
+	return TmuxKillPane(r.PaneId)
+}
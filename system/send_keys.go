@@ -0,0 +1,113 @@
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// DefaultSendKeysPollInterval is how often TmuxSendKeys polls pane state
+// while waiting for SendKeysOptions.ExpectRegexp to match, when
+// PollInterval is unset.
+const DefaultSendKeysPollInterval = 150 * time.Millisecond
+
+// sendKeysCaptureLines is how much of the pane's tail TmuxSendKeys checks
+// ExpectRegexp against, alongside #{pane_current_command}.
+const sendKeysCaptureLines = 5
+
+// SendKeysOptions controls how TmuxSendKeys sends keys and whether it
+// verifies delivery before returning.
+type SendKeysOptions struct {
+	// Literal sends keys via tmux's -l flag (as literal text) instead of
+	// interpreting them as key names, and skips appending an Enter
+	// keypress - a caller wanting one after literal text should issue it
+	// as a separate TmuxSendKeys call.
+	Literal bool
+
+	// Timeout, if non-zero, makes TmuxSendKeys poll pane state after
+	// sending until ExpectRegexp matches or Timeout elapses, returning a
+	// *SendKeysTimeoutError in the latter case. A zero Timeout (the
+	// default) is fire-and-forget: TmuxSendKeys returns as soon as the
+	// keys are delivered.
+	Timeout time.Duration
+	// ExpectRegexp is matched against both #{pane_current_command} and
+	// the last few lines of capture-pane; either matching satisfies the
+	// wait. Required when Timeout is non-zero.
+	ExpectRegexp *regexp.Regexp
+	// PollInterval overrides DefaultSendKeysPollInterval.
+	PollInterval time.Duration
+}
+
+// SendKeysTimeoutError is returned by TmuxSendKeys when verification is
+// requested but the pane never reaches the expected state within the
+// configured timeout - e.g. a command is blocked on a password prompt or
+// sitting in an editor. Callers can type-assert it to retry or surface a
+// specific message to the LLM instead of treating it as an opaque error.
+type SendKeysTimeoutError struct {
+	Target  string
+	Keys    string
+	Timeout time.Duration
+}
+
+func (e *SendKeysTimeoutError) Error() string {
+	return fmt.Sprintf("tmux: %q sent to %s did not reach the expected state within %s", e.Keys, e.Target, e.Timeout)
+}
+
+// TmuxSendKeys sends keys to target and, per opts, optionally verifies
+// that the pane reached an expected state before returning. Without
+// verification (the zero SendKeysOptions), this sends keys followed by
+// Enter and returns as soon as they're delivered, same as the previous
+// fire-and-forget TmuxSendKeys.
+func TmuxSendKeys(target, keys string, opts SendKeysOptions) error {
+	args := []string{"send-keys", "-t", target}
+	if opts.Literal {
+		args = append(args, "-l")
+	}
+	args = append(args, keys)
+	if !opts.Literal {
+		args = append(args, "Enter")
+	}
+
+	cmd := exec.Command("tmux", tmuxArgs(args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to send keys to %s: %v, stderr: %s", target, err, stderr.String())
+		return err
+	}
+
+	if opts.Timeout <= 0 || opts.ExpectRegexp == nil {
+		return nil
+	}
+
+	return waitForPaneState(target, keys, opts)
+}
+
+// waitForPaneState polls target until opts.ExpectRegexp matches its
+// current command or the tail of its content, or opts.Timeout elapses.
+func waitForPaneState(target, keys string, opts SendKeysOptions) error {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultSendKeysPollInterval
+	}
+	deadline := time.Now().Add(opts.Timeout)
+
+	for {
+		if cmdName, err := TmuxPaneCurrentCommand(target); err == nil && opts.ExpectRegexp.MatchString(cmdName) {
+			return nil
+		}
+		if tail, err := TmuxCapturePane(target, sendKeysCaptureLines); err == nil && opts.ExpectRegexp.MatchString(tail) {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return &SendKeysTimeoutError{Target: target, Keys: keys, Timeout: opts.Timeout}
+		}
+		time.Sleep(interval)
+	}
+}
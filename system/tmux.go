@@ -13,7 +13,17 @@ import (
 
 // TmuxCreateNewPane creates a new vertical split pane in the specified window and returns its ID
 func TmuxCreateNewPane(target string) (string, error) {
-	cmd := exec.Command("tmux", "split-window", "-d", "-v", "-t", target, "-P", "-F", "#{pane_id}")
+	if tc := activeTmuxController(); tc != nil {
+		lines, err := tc.RunCommand(fmt.Sprintf("split-window -d -v -t %s -P -F #{pane_id}", target))
+		if err == nil {
+			if paneId := strings.TrimSpace(strings.Join(lines, "\n")); paneId != "" {
+				return paneId, nil
+			}
+		}
+		logger.Debug("Control-mode split-window failed for target %s, falling back to exec: %v", target, err)
+	}
+
+	cmd := exec.Command("tmux", tmuxArgs("split-window", "-d", "-v", "-t", target, "-P", "-F", "#{pane_id}")...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -30,18 +40,31 @@ func TmuxCreateNewPane(target string) (string, error) {
 
 // TmuxPanesDetails gets details for all panes in a target window
 var TmuxPanesDetails = func(target string) ([]TmuxPaneDetails, error) {
-	cmd := exec.Command("tmux", "list-panes", "-t", target, "-F", "#{pane_id},#{pane_active},#{pane_pid},#{pane_current_command},#{history_size},#{history_limit}")
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	format := "#{pane_id},#{pane_active},#{pane_pid},#{pane_current_command},#{history_size},#{history_limit}"
+
+	var output string
+	if tc := activeTmuxController(); tc != nil {
+		lines, err := tc.RunCommand(fmt.Sprintf("list-panes -t %s -F %s", target, format))
+		if err == nil {
+			output = strings.TrimSpace(strings.Join(lines, "\n"))
+		} else {
+			logger.Debug("Control-mode list-panes failed for target %s, falling back to exec: %v", target, err)
+		}
+	}
 
-	err := cmd.Run()
-	if err != nil {
-		logger.Error("Failed to get tmux pane details for target %s %v, stderr: %s", target, err, stderr.String())
-		return nil, err
+	if output == "" {
+		cmd := exec.Command("tmux", tmuxArgs("list-panes", "-t", target, "-F", format)...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			logger.Error("Failed to get tmux pane details for target %s %v, stderr: %s", target, err, stderr.String())
+			return nil, err
+		}
+		output = strings.TrimSpace(stdout.String())
 	}
 
-	output := strings.TrimSpace(stdout.String())
 	if output == "" {
 		return nil, fmt.Errorf("no pane details found for target %s", target)
 	}
@@ -94,7 +117,15 @@ var TmuxPanesDetails = func(target string) ([]TmuxPaneDetails, error) {
 
 // TmuxCapturePane gets the content of a specific pane by ID
 var TmuxCapturePane = func(paneId string, maxLines int) (string, error) {
-	cmd := exec.Command("tmux", "capture-pane", "-p", "-t", paneId, "-S", fmt.Sprintf("-%d", maxLines))
+	if tc := activeTmuxController(); tc != nil {
+		lines, err := tc.RunCommand(fmt.Sprintf("capture-pane -p -t %s -S -%d", paneId, maxLines))
+		if err == nil {
+			return strings.TrimSpace(strings.Join(lines, "\n")), nil
+		}
+		logger.Debug("Control-mode capture-pane failed for %s, falling back to exec: %v", paneId, err)
+	}
+
+	cmd := exec.Command("tmux", tmuxArgs("capture-pane", "-p", "-t", paneId, "-S", fmt.Sprintf("-%d", maxLines))...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -116,7 +147,7 @@ func TmuxCurrentWindowTarget() (string, error) {
 		return "", err
 	}
 
-	cmd := exec.Command("tmux", "list-panes", "-t", paneId, "-F", "#{session_id}:#{window_index}")
+	cmd := exec.Command("tmux", tmuxArgs("list-panes", "-t", paneId, "-F", "#{session_id}:#{window_index}")...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get window target: %w", err)
@@ -145,7 +176,7 @@ var TmuxCurrentPaneId = func() (string, error) {
 
 // CreateTmuxSession creates a new tmux session and returns the new pane id
 func TmuxCreateSession() (string, error) {
-	cmd := exec.Command("tmux", "new-session", "-d", "-P", "-F", "#{pane_id}")
+	cmd := exec.Command("tmux", tmuxArgs("new-session", "-d", "-P", "-F", "#{pane_id}")...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -159,7 +190,7 @@ func TmuxCreateSession() (string, error) {
 
 // AttachToTmuxSession attaches to an existing tmux session
 func TmuxAttachSession(paneId string) error {
-	cmd := exec.Command("tmux", "attach-session", "-t", paneId)
+	cmd := exec.Command("tmux", tmuxArgs("attach-session", "-t", paneId)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -180,7 +211,7 @@ func TmuxExecSession(args []string) error {
 	}
 
 	// Create command with proper I/O handling
-	cmd := exec.Command(tmuxPath, args[1:]...)
+	cmd := exec.Command(tmuxPath, tmuxArgs(args[1:]...)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -190,57 +221,434 @@ func TmuxExecSession(args []string) error {
 }
 
 func TmuxClearPane(paneId string) error {
-	paneDetails, err := TmuxPanesDetails(paneId)
+	if tc := activeTmuxController(); tc != nil {
+		if _, err := tc.RunCommand(fmt.Sprintf("clear-history -t %s", paneId)); err == nil {
+			if err := TmuxSendRawKeys(paneId, "C-l"); err != nil {
+				logger.Error("Failed to send clear-screen keys to pane %s: %v", paneId, err)
+				return err
+			}
+			logger.Debug("Successfully cleared pane %s", paneId)
+			return nil
+		} else {
+			logger.Debug("Control-mode clear-history failed for %s, falling back to exec: %v", paneId, err)
+		}
+	}
+
+	cmd := exec.Command("tmux", tmuxArgs("clear-history", "-t", paneId)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to clear history for pane %s: %v, stderr: %s", paneId, err, stderr.String())
+		return err
+	}
+
+	if err := TmuxSendRawKeys(paneId, "C-l"); err != nil {
+		logger.Error("Failed to send clear-screen keys to pane %s: %v", paneId, err)
+		return err
+	}
+
+	logger.Debug("Successfully cleared pane %s", paneId)
+	return nil
+}
+
+// TmuxSetPaneTitle sets the title of a tmux pane
+func TmuxSetPaneTitle(paneId string, title string) error {
+	if tc := activeTmuxController(); tc != nil {
+		_, err := tc.RunCommand(fmt.Sprintf("select-pane -t %s -T %q", paneId, title))
+		if err == nil {
+			logger.Debug("Set pane title for %s to: %s", paneId, title)
+			return nil
+		}
+		logger.Debug("Control-mode select-pane failed for %s, falling back to exec: %v", paneId, err)
+	}
+
+	cmd := exec.Command("tmux", tmuxArgs("select-pane", "-t", paneId, "-T", title)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
 	if err != nil {
-		logger.Error("Failed to get pane details for %s: %v", paneId, err)
+		logger.Error("Failed to set pane title for %s: %v, stderr: %s", paneId, err, stderr.String())
 		return err
 	}
 
-	if len(paneDetails) == 0 {
-		return fmt.Errorf("no pane details found for pane %s", paneId)
+	logger.Debug("Set pane title for %s to: %s", paneId, title)
+	return nil
+}
+
+// TmuxHasSession reports whether a tmux session named name already exists.
+func TmuxHasSession(name string) bool {
+	cmd := exec.Command("tmux", tmuxArgs("has-session", "-t", name)...)
+	return cmd.Run() == nil
+}
+
+// TmuxNewWindow creates a new window in the given session and returns its
+// window ID (e.g. "@3").
+func TmuxNewWindow(sessionTarget, name string) (string, error) {
+	args := []string{"new-window", "-d", "-t", sessionTarget, "-P", "-F", "#{window_id}"}
+	if name != "" {
+		args = append(args, "-n", name)
 	}
 
-	cmd := exec.Command("tmux", "split-window", "-vp", "100", "-t", paneId)
+	cmd := exec.Command("tmux", tmuxArgs(args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
 	if err := cmd.Run(); err != nil {
-		logger.Error("Failed to split window for pane %s: %v", paneId, err)
+		logger.Error("Failed to create tmux window %q in %s: %v, stderr: %s", name, sessionTarget, err, stderr.String())
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// TmuxSplitPane splits target in the given direction ("v" for vertical,
+// "h" for horizontal) and returns the new pane's ID. percent is the new
+// pane's size as a percentage of target; 0 leaves it at tmux's default
+// (roughly even) split.
+func TmuxSplitPane(target, direction string, percent int) (string, error) {
+	args := []string{"split-window", "-d", "-" + direction, "-t", target, "-P", "-F", "#{pane_id}"}
+	if percent > 0 {
+		args = append(args, "-p", strconv.Itoa(percent))
+	}
+
+	cmd := exec.Command("tmux", tmuxArgs(args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to split pane %s (%s, %d%%): %v, stderr: %s", target, direction, percent, err, stderr.String())
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// TmuxSendRawKeys sends keys to target verbatim, without appending Enter,
+// so callers can pass tmux key names like "C-c" or "C-l" that aren't meant
+// to be typed as literal text.
+func TmuxSendRawKeys(target string, keys ...string) error {
+	if tc := activeTmuxController(); tc != nil {
+		cmd := append([]string{"send-keys", "-t", target}, keys...)
+		if _, err := tc.RunCommand(strings.Join(cmd, " ")); err == nil {
+			return nil
+		} else {
+			logger.Debug("Control-mode send-keys failed for %s, falling back to exec: %v", target, err)
+		}
+	}
+
+	args := append([]string{"send-keys", "-t", target}, keys...)
+	cmd := exec.Command("tmux", tmuxArgs(args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to send raw keys %v to %s: %v, stderr: %s", keys, target, err, stderr.String())
 		return err
 	}
 
-	cmd = exec.Command("tmux", "clear-vistory", "-t", paneId)
+	return nil
+}
+
+// TmuxToggleZoom toggles target's pane between its normal size and
+// filling the whole window.
+func TmuxToggleZoom(target string) error {
+	cmd := exec.Command("tmux", tmuxArgs("resize-pane", "-Z", "-t", target)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
 	if err := cmd.Run(); err != nil {
-		logger.Error("Failed to clear history for pane %s: %v", paneId, err)
+		logger.Error("Failed to toggle zoom for %s: %v, stderr: %s", target, err, stderr.String())
 		return err
 	}
 
-	cmd = exec.Command("tmux", "kill-pane")
+	return nil
+}
+
+// TmuxFindPaneByTitle returns the id of the first pane in windowTarget
+// whose title equals title, or "" if none matches.
+func TmuxFindPaneByTitle(windowTarget, title string) (string, error) {
+	cmd := exec.Command("tmux", tmuxArgs("list-panes", "-t", windowTarget, "-F", "#{pane_id},#{pane_title}")...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to list panes by title in %s: %v, stderr: %s", windowTarget, err, stderr.String())
+		return "", err
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		parts := strings.SplitN(line, ",", 2)
+		if len(parts) == 2 && parts[1] == title {
+			return parts[0], nil
+		}
+	}
+
+	return "", nil
+}
+
+// TmuxSelectLayout applies a built-in tmux layout preset (e.g.
+// "even-vertical", "tiled", "main-horizontal") to the window containing
+// target.
+func TmuxSelectLayout(target, layout string) error {
+	cmd := exec.Command("tmux", tmuxArgs("select-layout", "-t", target, layout)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
 	if err := cmd.Run(); err != nil {
-		logger.Error("Failed to kill temporary pane: %v", err)
+		logger.Error("Failed to select layout %q for %s: %v, stderr: %s", layout, target, err, stderr.String())
 		return err
 	}
 
-	logger.Debug("Successfully cleared pane %s", paneId)
 	return nil
 }
 
-// TmuxSetPaneTitle sets the title of a tmux pane
-func TmuxSetPaneTitle(paneId string, title string) error {
-	cmd := exec.Command("tmux", "select-pane", "-t", paneId, "-T", title)
+// TmuxSelectWindow makes target the active window in its session.
+func TmuxSelectWindow(target string) error {
+	cmd := exec.Command("tmux", tmuxArgs("select-window", "-t", target)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
-	if err != nil {
-		logger.Error("Failed to set pane title for %s: %v, stderr: %s", paneId, err, stderr.String())
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to select window %s: %v, stderr: %s", target, err, stderr.String())
+		return err
+	}
+
+	return nil
+}
+
+// TmuxRenameSession renames the current (most recently created) tmux
+// session to name.
+func TmuxRenameSession(name string) error {
+	cmd := exec.Command("tmux", tmuxArgs("rename-session", name)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to rename session to %q: %v, stderr: %s", name, err, stderr.String())
+		return err
+	}
+
+	return nil
+}
+
+// TmuxRenameWindow renames target to name.
+func TmuxRenameWindow(target, name string) error {
+	cmd := exec.Command("tmux", tmuxArgs("rename-window", "-t", target, name)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to rename window %s to %q: %v, stderr: %s", target, name, err, stderr.String())
 		return err
 	}
 
-	logger.Debug("Set pane title for %s to: %s", paneId, title)
 	return nil
 }
 
+// TmuxWindowNames returns the names of every window in sessionTarget.
+func TmuxWindowNames(sessionTarget string) ([]string, error) {
+	cmd := exec.Command("tmux", tmuxArgs("list-windows", "-t", sessionTarget, "-F", "#{window_name}")...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to list windows for session %s: %v, stderr: %s", sessionTarget, err, stderr.String())
+		return nil, err
+	}
+
+	output := strings.TrimSpace(stdout.String())
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// TmuxSetSyncPanes enables or disables tmux's synchronize-panes option
+// (keystrokes sent to one pane echoed to every pane) for target's window.
+func TmuxSetSyncPanes(target string, on bool) error {
+	value := "off"
+	if on {
+		value = "on"
+	}
+
+	cmd := exec.Command("tmux", tmuxArgs("set-window-option", "-t", target, "synchronize-panes", value)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to set synchronize-panes=%s for %s: %v, stderr: %s", value, target, err, stderr.String())
+		return err
+	}
+
+	return nil
+}
+
+// TmuxWindowInfo is one row of `tmux list-windows`, as needed by
+// layout.DumpSession to reconstruct a session's window/layout structure.
+type TmuxWindowInfo struct {
+	Id     string
+	Name   string
+	Layout string
+	Width  int
+	Height int
+}
+
+// TmuxWindowsInfo lists every window in sessionTarget with its id, name,
+// tmux layout string (e.g. from #{window_layout}) and dimensions.
+func TmuxWindowsInfo(sessionTarget string) ([]TmuxWindowInfo, error) {
+	format := "#{window_id},#{window_name},#{window_layout},#{window_width},#{window_height}"
+	cmd := exec.Command("tmux", tmuxArgs("list-windows", "-t", sessionTarget, "-F", format)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to list window info for session %s: %v, stderr: %s", sessionTarget, err, stderr.String())
+		return nil, err
+	}
+
+	output := strings.TrimSpace(stdout.String())
+	if output == "" {
+		return nil, nil
+	}
+
+	var windows []TmuxWindowInfo
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ",", 5)
+		if len(parts) < 5 {
+			logger.Error("Invalid window info format for line: %s", line)
+			continue
+		}
+
+		width, _ := strconv.Atoi(parts[3])
+		height, _ := strconv.Atoi(parts[4])
+
+		windows = append(windows, TmuxWindowInfo{
+			Id:     parts[0],
+			Name:   parts[1],
+			Layout: parts[2],
+			Width:  width,
+			Height: height,
+		})
+	}
+
+	return windows, nil
+}
+
+// TmuxPaneCurrentPath returns the working directory tmux considers paneId
+// to currently be in.
+func TmuxPaneCurrentPath(paneId string) (string, error) {
+	cmd := exec.Command("tmux", tmuxArgs("display-message", "-t", paneId, "-p", "#{pane_current_path}")...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to get current path for pane %s: %v, stderr: %s", paneId, err, stderr.String())
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// TmuxPaneTitle returns paneId's current title.
+func TmuxPaneTitle(paneId string) (string, error) {
+	cmd := exec.Command("tmux", tmuxArgs("display-message", "-t", paneId, "-p", "#{pane_title}")...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to get title for pane %s: %v, stderr: %s", paneId, err, stderr.String())
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// TmuxPaneCurrentCommand returns the name of the command currently running
+// in paneId (e.g. "bash", "vim", "ssh").
+func TmuxPaneCurrentCommand(paneId string) (string, error) {
+	cmd := exec.Command("tmux", tmuxArgs("display-message", "-t", paneId, "-p", "#{pane_current_command}")...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to get current command for pane %s: %v, stderr: %s", paneId, err, stderr.String())
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// TmuxPaneRect is a pane's position and size in its window, in cells, as
+// needed to reconstruct a split ratio.
+type TmuxPaneRect struct {
+	Id      string
+	Active  bool
+	Top     int
+	Left    int
+	Width   int
+	Height  int
+	Command string
+}
+
+// TmuxPanesRects lists every pane in windowTarget with its rectangle and
+// current command, in tmux's natural (top-to-bottom, left-to-right) order.
+func TmuxPanesRects(windowTarget string) ([]TmuxPaneRect, error) {
+	format := "#{pane_id},#{pane_active},#{pane_top},#{pane_left},#{pane_width},#{pane_height},#{pane_current_command}"
+	cmd := exec.Command("tmux", tmuxArgs("list-panes", "-t", windowTarget, "-F", format)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to list pane rects for window %s: %v, stderr: %s", windowTarget, err, stderr.String())
+		return nil, err
+	}
+
+	output := strings.TrimSpace(stdout.String())
+	if output == "" {
+		return nil, nil
+	}
+
+	var rects []TmuxPaneRect
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ",", 7)
+		if len(parts) < 7 {
+			logger.Error("Invalid pane rect format for line: %s", line)
+			continue
+		}
+
+		top, _ := strconv.Atoi(parts[2])
+		left, _ := strconv.Atoi(parts[3])
+		width, _ := strconv.Atoi(parts[4])
+		height, _ := strconv.Atoi(parts[5])
+
+		rects = append(rects, TmuxPaneRect{
+			Id:      parts[0],
+			Active:  parts[1] == "1",
+			Top:     top,
+			Left:    left,
+			Width:   width,
+			Height:  height,
+			Command: parts[6],
+		})
+	}
+
+	return rects, nil
+}
+
 // TmuxKillPane kills a specific tmux pane
 func TmuxKillPane(paneId string) error {
-	cmd := exec.Command("tmux", "kill-pane", "-t", paneId)
+	cmd := exec.Command("tmux", tmuxArgs("kill-pane", "-t", paneId)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -272,7 +680,7 @@ func TmuxSwitchToOtherPane(chatPaneId, execPaneId string) error {
 	}
 
 	// Switch to the target pane
-	cmd := exec.Command("tmux", "select-pane", "-t", targetPane)
+	cmd := exec.Command("tmux", tmuxArgs("select-pane", "-t", targetPane)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -295,7 +703,7 @@ func TmuxSetupPaneSwitchBinding(chatPaneId, execPaneId string) error {
 		chatPaneId, execPaneId, chatPaneId,
 	)
 
-	cmd := exec.Command("tmux", "bind-key", "-n", "BTab", switchCmd)
+	cmd := exec.Command("tmux", tmuxArgs("bind-key", "-n", "BTab", switchCmd)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -313,11 +721,11 @@ func TmuxSetupPaneSwitchBinding(chatPaneId, execPaneId string) error {
 func TmuxSetupPaneToggleBinding(chatPaneId, execPaneId string) error {
 	// First unbind any existing S-Down bindings to avoid conflicts
 	// Unbind from root table (our custom binding from previous session)
-	unbindCmd := exec.Command("tmux", "unbind-key", "-n", "S-Down")
+	unbindCmd := exec.Command("tmux", tmuxArgs("unbind-key", "-n", "S-Down")...)
 	_ = unbindCmd.Run() // Ignore error if no binding exists
 
 	// Unbind from prefix table (default tmux binding)
-	unbindCmd = exec.Command("tmux", "unbind-key", "-T", "prefix", "S-Down")
+	unbindCmd = exec.Command("tmux", tmuxArgs("unbind-key", "-T", "prefix", "S-Down")...)
 	_ = unbindCmd.Run() // Ignore error if no binding exists
 
 	// Use pure tmux commands - if chat pane is small, expand it; otherwise collapse it
@@ -332,7 +740,7 @@ func TmuxSetupPaneToggleBinding(chatPaneId, execPaneId string) error {
 	)
 
 	// Shift+Down arrow is represented as S-Down in tmux
-	cmd := exec.Command("tmux", "bind-key", "-n", "S-Down", toggleCmd)
+	cmd := exec.Command("tmux", tmuxArgs("bind-key", "-n", "S-Down", toggleCmd)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -359,7 +767,7 @@ func TmuxSetupStyling() error {
 	}
 
 	for _, cmdArgs := range commands {
-		cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+		cmd := exec.Command(cmdArgs[0], tmuxArgs(cmdArgs[1:]...)...)
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
 
@@ -378,7 +786,7 @@ func TmuxSetupStyling() error {
 func TmuxUpdateStatusBar(modelName, provider string) error {
 	statusText := fmt.Sprintf("aiterm | model: %s (%s)", modelName, provider)
 
-	cmd := exec.Command("tmux", "set", "-g", "status-right", statusText)
+	cmd := exec.Command("tmux", tmuxArgs("set", "-g", "status-right", statusText)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -394,7 +802,7 @@ func TmuxUpdateStatusBar(modelName, provider string) error {
 
 // TmuxGetPaneHeight gets the height of a specific pane
 func TmuxGetPaneHeight(paneId string) (int, error) {
-	cmd := exec.Command("tmux", "display-message", "-t", paneId, "-p", "#{pane_height}")
+	cmd := exec.Command("tmux", tmuxArgs("display-message", "-t", paneId, "-p", "#{pane_height}")...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -417,7 +825,16 @@ func TmuxGetPaneHeight(paneId string) (int, error) {
 
 // TmuxResizePane resizes a pane to a specific height
 func TmuxResizePane(paneId string, height int) error {
-	cmd := exec.Command("tmux", "resize-pane", "-t", paneId, "-y", strconv.Itoa(height))
+	if tc := activeTmuxController(); tc != nil {
+		_, err := tc.RunCommand(fmt.Sprintf("resize-pane -t %s -y %d", paneId, height))
+		if err == nil {
+			logger.Debug("Resized pane %s to height %d", paneId, height)
+			return nil
+		}
+		logger.Debug("Control-mode resize-pane failed for %s, falling back to exec: %v", paneId, err)
+	}
+
+	cmd := exec.Command("tmux", tmuxArgs("resize-pane", "-t", paneId, "-y", strconv.Itoa(height))...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -447,13 +864,13 @@ func TmuxTogglePaneCollapse(chatPaneId, execPaneId string) error {
 			return err
 		}
 		// Make panes equal size
-		cmd := exec.Command("tmux", "select-layout", "even-vertical")
+		cmd := exec.Command("tmux", tmuxArgs("select-layout", "even-vertical")...)
 		if err := cmd.Run(); err != nil {
 			logger.Error("Failed to set even layout: %v", err)
 			return err
 		}
 		// Focus chat pane
-		cmd = exec.Command("tmux", "select-pane", "-t", chatPaneId)
+		cmd = exec.Command("tmux", tmuxArgs("select-pane", "-t", chatPaneId)...)
 		if err := cmd.Run(); err != nil {
 			return err
 		}
@@ -465,7 +882,7 @@ func TmuxTogglePaneCollapse(chatPaneId, execPaneId string) error {
 			return err
 		}
 		// Focus exec pane
-		cmd := exec.Command("tmux", "select-pane", "-t", execPaneId)
+		cmd := exec.Command("tmux", tmuxArgs("select-pane", "-t", execPaneId)...)
 		if err := cmd.Run(); err != nil {
 			return err
 		}
@@ -478,17 +895,17 @@ func TmuxTogglePaneCollapse(chatPaneId, execPaneId string) error {
 // TmuxSetupPaneResizeBindings sets up Shift+Up and Shift+Down to resize the active pane
 func TmuxSetupPaneResizeBindings() error {
 	// Unbind any existing S-Up and S-Down bindings
-	unbindUp := exec.Command("tmux", "unbind-key", "-n", "S-Up")
+	unbindUp := exec.Command("tmux", tmuxArgs("unbind-key", "-n", "S-Up")...)
 	_ = unbindUp.Run()
-	unbindDown := exec.Command("tmux", "unbind-key", "-n", "S-Down")
+	unbindDown := exec.Command("tmux", tmuxArgs("unbind-key", "-n", "S-Down")...)
 	_ = unbindDown.Run()
-	unbindUp = exec.Command("tmux", "unbind-key", "-T", "prefix", "S-Up")
+	unbindUp = exec.Command("tmux", tmuxArgs("unbind-key", "-T", "prefix", "S-Up")...)
 	_ = unbindUp.Run()
-	unbindDown = exec.Command("tmux", "unbind-key", "-T", "prefix", "S-Down")
+	unbindDown = exec.Command("tmux", tmuxArgs("unbind-key", "-T", "prefix", "S-Down")...)
 	_ = unbindDown.Run()
 
 	// Bind S-Up to resize up
-	cmd := exec.Command("tmux", "bind-key", "-r", "-n", "S-Up", "resize-pane", "-U", "1")
+	cmd := exec.Command("tmux", tmuxArgs("bind-key", "-r", "-n", "S-Up", "resize-pane", "-U", "1")...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	err := cmd.Run()
@@ -498,7 +915,7 @@ func TmuxSetupPaneResizeBindings() error {
 	}
 
 	// Bind S-Down to resize down
-	cmd = exec.Command("tmux", "bind-key", "-r", "-n", "S-Down", "resize-pane", "-D", "1")
+	cmd = exec.Command("tmux", tmuxArgs("bind-key", "-r", "-n", "S-Down", "resize-pane", "-D", "1")...)
 	cmd.Stderr = &stderr
 	err = cmd.Run()
 	if err != nil {
@@ -512,7 +929,7 @@ func TmuxSetupPaneResizeBindings() error {
 
 // TmuxSwapPane swaps the specified pane with the one in the given direction
 func TmuxSwapPane(paneId, direction string) error {
-	cmd := exec.Command("tmux", "swap-pane", direction, "-t", paneId)
+	cmd := exec.Command("tmux", tmuxArgs("swap-pane", direction, "-t", paneId)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	err := cmd.Run()
@@ -527,7 +944,7 @@ func TmuxSwapPane(paneId, direction string) error {
 
 // TmuxSelectPane selects the specified pane
 func TmuxSelectPane(paneId string) error {
-	cmd := exec.Command("tmux", "select-pane", "-t", paneId)
+	cmd := exec.Command("tmux", tmuxArgs("select-pane", "-t", paneId)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	err := cmd.Run()
@@ -543,7 +960,7 @@ func TmuxSelectPane(paneId string) error {
 // TmuxSetupPaneScrollBindings sets up Ctrl+Up and Ctrl+Down for scrolling the current pane
 func TmuxSetupPaneScrollBindings() error {
 	// Bind M-Up to scroll up
-	cmd := exec.Command("tmux", "bind-key", "-n", "M-Up", "copy-mode", ";", "send-keys", "Up")
+	cmd := exec.Command("tmux", tmuxArgs("bind-key", "-n", "M-Up", "copy-mode", ";", "send-keys", "Up")...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	err := cmd.Run()
@@ -553,7 +970,7 @@ func TmuxSetupPaneScrollBindings() error {
 	}
 
 	// Bind M-Down to scroll down
-	cmd = exec.Command("tmux", "bind-key", "-n", "M-Down", "copy-mode", ";", "send-keys", "Down")
+	cmd = exec.Command("tmux", tmuxArgs("bind-key", "-n", "M-Down", "copy-mode", ";", "send-keys", "Down")...)
 	cmd.Stderr = &stderr
 	err = cmd.Run()
 	if err != nil {
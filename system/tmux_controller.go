@@ -0,0 +1,252 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andreim2k/aiterm/logger"
+)
+
+// TmuxEventType identifies an asynchronous notification tmux control mode
+// pushes outside of a %begin/%end reply block.
+type TmuxEventType string
+
+const (
+	TmuxEventOutput        TmuxEventType = "output"
+	TmuxEventWindowAdd     TmuxEventType = "window-add"
+	TmuxEventLayoutChange  TmuxEventType = "layout-change"
+	TmuxEventSessionChange TmuxEventType = "session-changed"
+)
+
+// TmuxEvent is a single asynchronous notification read off a
+// TmuxController's control-mode connection: a pane producing output, a
+// window being added, a layout change, or the attached client switching
+// sessions. PaneId/Data are populated as relevant to Type and otherwise
+// left zero.
+type TmuxEvent struct {
+	Type   TmuxEventType
+	PaneId string
+	Data   string
+}
+
+// DefaultTmuxCommandTimeout bounds how long RunCommand waits for a
+// %begin/%end (or %error) reply before giving up.
+const DefaultTmuxCommandTimeout = 5 * time.Second
+
+// tmuxPendingCommand tracks one in-flight RunCommand call, matched to its
+// %begin/%end reply by FIFO order on TmuxController.pending.
+type tmuxPendingCommand struct {
+	resultLines []string
+	done        chan error
+}
+
+// TmuxController is a single long-lived `tmux -C attach-session` (control
+// mode) connection. Helpers that used to fork a fresh `tmux ...` subprocess
+// per call can submit a command string here instead and get back its
+// reply, amortizing process-spawn cost across the session and avoiding the
+// job-control notifications a detached `&` subprocess triggers in the
+// parent shell. Asynchronous notifications (%output, %window-add,
+// %layout-change, %session-changed) are published on Events instead of
+// being polled for with repeated list-panes/display-message calls.
+type TmuxController struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	Events chan TmuxEvent
+
+	mu      sync.Mutex
+	pending []*tmuxPendingCommand
+}
+
+// NewTmuxController attaches to target in control mode and starts the
+// background reader goroutine. Call Close when done with it.
+func NewTmuxController(target string) (*TmuxController, error) {
+	cmd := exec.Command("tmux", tmuxArgs("-C", "attach-session", "-t", target)...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tmux control mode stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tmux control mode stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start tmux control mode: %w", err)
+	}
+
+	tc := &TmuxController{
+		cmd:    cmd,
+		stdin:  stdin,
+		Events: make(chan TmuxEvent, 64),
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	go tc.readLoop(scanner)
+
+	logger.Debug("Attached tmux control mode connection to %s", target)
+	return tc, nil
+}
+
+var (
+	tmuxBeginRegex        = regexp.MustCompile(`^%begin \d+ \d+ \d+$`)
+	tmuxEndRegex          = regexp.MustCompile(`^%end \d+ \d+ \d+$`)
+	tmuxErrorRegex        = regexp.MustCompile(`^%error \d+ \d+ \d+$`)
+	tmuxOutputRegex       = regexp.MustCompile(`^%output (%\S+) (.*)$`)
+	tmuxWindowAddRegex    = regexp.MustCompile(`^%window-add (@\S+)$`)
+	tmuxLayoutChangeRegex = regexp.MustCompile(`^%layout-change (@\S+) (.*)$`)
+)
+
+// readLoop demultiplexes the control-mode stream into %begin/%end/%error
+// reply blocks (delivered to the matching pending RunCommand caller) and
+// everything else (published to Events as a TmuxEvent).
+func (tc *TmuxController) readLoop(scanner *bufio.Scanner) {
+	defer close(tc.Events)
+
+	var current *tmuxPendingCommand
+	var failed bool
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case tmuxBeginRegex.MatchString(line):
+			tc.mu.Lock()
+			if len(tc.pending) > 0 {
+				current = tc.pending[0]
+			} else {
+				current = nil
+			}
+			tc.mu.Unlock()
+			failed = false
+			continue
+
+		case tmuxEndRegex.MatchString(line), tmuxErrorRegex.MatchString(line):
+			failed = tmuxErrorRegex.MatchString(line)
+			if current != nil {
+				tc.mu.Lock()
+				if len(tc.pending) > 0 {
+					tc.pending = tc.pending[1:]
+				}
+				tc.mu.Unlock()
+
+				var err error
+				if failed {
+					err = fmt.Errorf("tmux: %s", strings.Join(current.resultLines, "\n"))
+				}
+				current.done <- err
+			}
+			current = nil
+			continue
+		}
+
+		if current != nil {
+			current.resultLines = append(current.resultLines, line)
+			continue
+		}
+
+		if event, ok := parseTmuxEvent(line); ok {
+			select {
+			case tc.Events <- event:
+			default:
+				logger.Debug("Dropped tmux control-mode event, Events channel full: %+v", event)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		logger.Error("tmux control mode read loop ended with error: %v", err)
+	}
+}
+
+// parseTmuxEvent recognizes the asynchronous notifications the UI layer
+// cares about; anything else (e.g. %exit, %sessions-changed) is ignored.
+func parseTmuxEvent(line string) (TmuxEvent, bool) {
+	if m := tmuxOutputRegex.FindStringSubmatch(line); m != nil {
+		return TmuxEvent{Type: TmuxEventOutput, PaneId: m[1], Data: m[2]}, true
+	}
+	if m := tmuxWindowAddRegex.FindStringSubmatch(line); m != nil {
+		return TmuxEvent{Type: TmuxEventWindowAdd, Data: m[1]}, true
+	}
+	if m := tmuxLayoutChangeRegex.FindStringSubmatch(line); m != nil {
+		return TmuxEvent{Type: TmuxEventLayoutChange, PaneId: m[1], Data: m[2]}, true
+	}
+	if strings.HasPrefix(line, "%session-changed ") {
+		return TmuxEvent{Type: TmuxEventSessionChange, Data: strings.TrimPrefix(line, "%session-changed ")}, true
+	}
+	return TmuxEvent{}, false
+}
+
+// RunCommand sends cmd (a plain tmux command-mode command, e.g.
+// "list-panes -t %1 -F ...") and returns the lines of its %begin/%end
+// reply. It returns an error if tmux replied with %error or if no reply
+// arrives within DefaultTmuxCommandTimeout. Safe for concurrent callers:
+// each submission is queued and matched to its reply in FIFO order.
+func (tc *TmuxController) RunCommand(cmd string) ([]string, error) {
+	pc := &tmuxPendingCommand{done: make(chan error, 1)}
+
+	tc.mu.Lock()
+	tc.pending = append(tc.pending, pc)
+	_, err := io.WriteString(tc.stdin, cmd+"\n")
+	tc.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write tmux command: %w", err)
+	}
+
+	select {
+	case err := <-pc.done:
+		return pc.resultLines, err
+	case <-time.After(DefaultTmuxCommandTimeout):
+		return nil, fmt.Errorf("tmux command %q timed out after %s", cmd, DefaultTmuxCommandTimeout)
+	}
+}
+
+// Close ends the control-mode connection and waits for the tmux client
+// process to exit.
+func (tc *TmuxController) Close() error {
+	_ = tc.stdin.Close()
+	return tc.cmd.Wait()
+}
+
+// tmuxCtl is the process-wide controller installed by EnableTmuxController,
+// if any. Helpers that know how to use it (TmuxCapturePane, TmuxPanesDetails,
+// TmuxSetPaneTitle, TmuxResizePane, TmuxCreateNewPane, TmuxClearPane,
+// TmuxSendRawKeys) check it first and fall back to the exec-based path when
+// it's unset or a command on it fails. Not every tmux helper is routed yet -
+// these are the hottest call sites (pane lifecycle and keystroke delivery).
+var (
+	tmuxControllerMu sync.Mutex
+	tmuxCtl          *TmuxController
+)
+
+// EnableTmuxController attaches a TmuxController to target and installs it
+// as the process-wide connection the exec-fallback helpers prefer. Call
+// this once, early in startup (e.g. from Manager initialization), after the
+// session/target pane already exists.
+func EnableTmuxController(target string) error {
+	tc, err := NewTmuxController(target)
+	if err != nil {
+		return err
+	}
+
+	tmuxControllerMu.Lock()
+	tmuxCtl = tc
+	tmuxControllerMu.Unlock()
+
+	return nil
+}
+
+// activeTmuxController returns the installed TmuxController, or nil if
+// EnableTmuxController hasn't been called (or failed).
+func activeTmuxController() *TmuxController {
+	tmuxControllerMu.Lock()
+	defer tmuxControllerMu.Unlock()
+	return tmuxCtl
+}
@@ -0,0 +1,127 @@
+package system
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func newScanner(input string) *bufio.Scanner {
+	return bufio.NewScanner(strings.NewReader(input))
+}
+
+func TestTmuxControllerReadLoopDeliversSuccessfulReply(t *testing.T) {
+	tc := &TmuxController{Events: make(chan TmuxEvent, 8)}
+	pc := &tmuxPendingCommand{done: make(chan error, 1)}
+	tc.pending = append(tc.pending, pc)
+
+	input := "%begin 1 1 1\n" +
+		"pane output line 1\n" +
+		"pane output line 2\n" +
+		"%end 1 1 1\n"
+	tc.readLoop(newScanner(input))
+
+	select {
+	case err := <-pc.done:
+		if err != nil {
+			t.Fatalf("expected nil error for %%end reply, got %v", err)
+		}
+	default:
+		t.Fatal("expected pc.done to be signaled")
+	}
+
+	want := []string{"pane output line 1", "pane output line 2"}
+	if len(pc.resultLines) != len(want) {
+		t.Fatalf("resultLines = %v, want %v", pc.resultLines, want)
+	}
+	for i, line := range want {
+		if pc.resultLines[i] != line {
+			t.Errorf("resultLines[%d] = %q, want %q", i, pc.resultLines[i], line)
+		}
+	}
+	if len(tc.pending) != 0 {
+		t.Errorf("expected pending to be drained, got %d entries", len(tc.pending))
+	}
+}
+
+func TestTmuxControllerReadLoopDeliversErrorReply(t *testing.T) {
+	tc := &TmuxController{Events: make(chan TmuxEvent, 8)}
+	pc := &tmuxPendingCommand{done: make(chan error, 1)}
+	tc.pending = append(tc.pending, pc)
+
+	input := "%begin 1 1 1\n" +
+		"can't find pane\n" +
+		"%error 1 1 1\n"
+	tc.readLoop(newScanner(input))
+
+	select {
+	case err := <-pc.done:
+		if err == nil {
+			t.Fatal("expected non-nil error for %error reply")
+		}
+		if !strings.Contains(err.Error(), "can't find pane") {
+			t.Errorf("error = %v, want it to contain the reply body", err)
+		}
+	default:
+		t.Fatal("expected pc.done to be signaled")
+	}
+}
+
+func TestTmuxControllerReadLoopMatchesRepliesFIFO(t *testing.T) {
+	tc := &TmuxController{Events: make(chan TmuxEvent, 8)}
+	first := &tmuxPendingCommand{done: make(chan error, 1)}
+	second := &tmuxPendingCommand{done: make(chan error, 1)}
+	tc.pending = append(tc.pending, first, second)
+
+	input := "%begin 1 1 1\n" +
+		"first reply\n" +
+		"%end 1 1 1\n" +
+		"%begin 2 2 2\n" +
+		"second reply\n" +
+		"%end 2 2 2\n"
+	tc.readLoop(newScanner(input))
+
+	if got := <-first.done; got != nil {
+		t.Errorf("first.done = %v, want nil", got)
+	}
+	if got := <-second.done; got != nil {
+		t.Errorf("second.done = %v, want nil", got)
+	}
+	if first.resultLines[0] != "first reply" {
+		t.Errorf("first.resultLines = %v, want [\"first reply\"]", first.resultLines)
+	}
+	if second.resultLines[0] != "second reply" {
+		t.Errorf("second.resultLines = %v, want [\"second reply\"]", second.resultLines)
+	}
+}
+
+func TestTmuxControllerReadLoopPublishesAsyncEvents(t *testing.T) {
+	tc := &TmuxController{Events: make(chan TmuxEvent, 8)}
+
+	input := "%output %3 hello\\sworld\n" +
+		"%window-add @4\n" +
+		"%layout-change @5 abcd,80x24,0,0\n" +
+		"%session-changed $1\n"
+	tc.readLoop(newScanner(input))
+
+	var events []TmuxEvent
+	for event := range tc.Events {
+		events = append(events, event)
+	}
+
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4: %+v", len(events), events)
+	}
+	if events[0].Type != TmuxEventOutput || events[0].PaneId != "%3" {
+		t.Errorf("events[0] = %+v, want Output for %%3", events[0])
+	}
+	if events[1].Type != TmuxEventWindowAdd || events[1].Data != "@4" {
+		t.Errorf("events[1] = %+v, want WindowAdd for @4", events[1])
+	}
+	if events[2].Type != TmuxEventLayoutChange || events[2].PaneId != "@5" {
+		t.Errorf("events[2] = %+v, want LayoutChange for @5", events[2])
+	}
+	if events[3].Type != TmuxEventSessionChange || events[3].Data != "$1" {
+		t.Errorf("events[3] = %+v, want SessionChange for $1", events[3])
+	}
+}
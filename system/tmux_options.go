@@ -0,0 +1,66 @@
+package system
+
+import "sync"
+
+// TmuxOptions selects which tmux server every helper in this package talks
+// to. Set it once at startup (before creating/attaching any session) via
+// SetTmuxOptions so aiterm's chat+exec panes run on a private server that
+// doesn't collide with the user's own tmux - bindings, options, the mouse
+// setting, and the status-right customization TmuxSetupStyling/
+// TmuxUpdateStatusBar apply won't leak into it - and so multiple concurrent
+// aiterm sessions on the same host don't cross-talk.
+type TmuxOptions struct {
+	// SocketName selects a named server under tmux's default socket
+	// directory, equivalent to tmux -L.
+	SocketName string
+	// SocketPath selects a server by an explicit socket path, equivalent
+	// to tmux -S. Takes precedence over SocketName if both are set, same
+	// as tmux's own -S/-L precedence.
+	SocketPath string
+	// ConfigFile, if set, is passed as tmux -f so the private server
+	// starts from a specific config instead of ~/.tmux.conf.
+	ConfigFile string
+}
+
+var (
+	tmuxOptionsMu sync.Mutex
+	tmuxOptions   TmuxOptions
+)
+
+// SetTmuxOptions installs opts as the options every subsequent tmux
+// invocation in this package uses.
+func SetTmuxOptions(opts TmuxOptions) {
+	tmuxOptionsMu.Lock()
+	tmuxOptions = opts
+	tmuxOptionsMu.Unlock()
+}
+
+// ActiveTmuxOptions returns the options installed by SetTmuxOptions (the
+// zero value, targeting the user's default tmux server, if it was never
+// called).
+func ActiveTmuxOptions() TmuxOptions {
+	tmuxOptionsMu.Lock()
+	defer tmuxOptionsMu.Unlock()
+	return tmuxOptions
+}
+
+// tmuxArgs prepends the active TmuxOptions' -L/-S/-f flags to args, for
+// building an exec.Command invocation of the tmux binary.
+func tmuxArgs(args ...string) []string {
+	opts := ActiveTmuxOptions()
+
+	var prefix []string
+	if opts.ConfigFile != "" {
+		prefix = append(prefix, "-f", opts.ConfigFile)
+	}
+	if opts.SocketPath != "" {
+		prefix = append(prefix, "-S", opts.SocketPath)
+	} else if opts.SocketName != "" {
+		prefix = append(prefix, "-L", opts.SocketName)
+	}
+
+	if len(prefix) == 0 {
+		return args
+	}
+	return append(prefix, args...)
+}
@@ -0,0 +1,54 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func withTmuxOptions(t *testing.T, opts TmuxOptions, fn func()) {
+	t.Helper()
+	prev := ActiveTmuxOptions()
+	SetTmuxOptions(opts)
+	defer SetTmuxOptions(prev)
+	fn()
+}
+
+func TestTmuxArgsNoOptionsLeavesArgsUnchanged(t *testing.T) {
+	withTmuxOptions(t, TmuxOptions{}, func() {
+		got := tmuxArgs("list-panes", "-t", "%1")
+		want := []string{"list-panes", "-t", "%1"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("tmuxArgs() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestTmuxArgsSocketName(t *testing.T) {
+	withTmuxOptions(t, TmuxOptions{SocketName: "aiterm"}, func() {
+		got := tmuxArgs("list-panes")
+		want := []string{"-L", "aiterm", "list-panes"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("tmuxArgs() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestTmuxArgsSocketPathTakesPrecedenceOverSocketName(t *testing.T) {
+	withTmuxOptions(t, TmuxOptions{SocketName: "aiterm", SocketPath: "/tmp/aiterm.sock"}, func() {
+		got := tmuxArgs("list-panes")
+		want := []string{"-S", "/tmp/aiterm.sock", "list-panes"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("tmuxArgs() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestTmuxArgsConfigFilePrecedesSocketFlag(t *testing.T) {
+	withTmuxOptions(t, TmuxOptions{ConfigFile: "/tmp/aiterm.conf", SocketName: "aiterm"}, func() {
+		got := tmuxArgs("list-panes")
+		want := []string{"-f", "/tmp/aiterm.conf", "-L", "aiterm", "list-panes"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("tmuxArgs() = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+// Package vectorstore persists (id, text, embedding, metadata) tuples in a
+// SQLite file and supports cosine-similarity top-K retrieval, used to index
+// prior shell commands and AI responses for retrieval-augmented translation.
+package vectorstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Record is a single indexed (id, text, embedding, metadata) tuple.
+type Record struct {
+	ID        string
+	Text      string
+	Embedding []float32
+	Metadata  map[string]string
+}
+
+// Scored wraps a Record with its similarity score against a query vector.
+type Scored struct {
+	Record
+	Score float32
+}
+
+// VectorStore is a SQLite-backed store of embedded text records.
+type VectorStore struct {
+	db *sql.DB
+}
+
+// Open opens (creating if necessary) a SQLite-backed VectorStore at path.
+func Open(path string) (*VectorStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create vector store dir: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vector store: %w", err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS records (
+	id        TEXT PRIMARY KEY,
+	text      TEXT NOT NULL,
+	embedding BLOB NOT NULL,
+	metadata  TEXT NOT NULL
+);`
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize vector store schema: %w", err)
+	}
+
+	return &VectorStore{db: db}, nil
+}
+
+// Close closes the underlying SQLite connection.
+func (s *VectorStore) Close() error {
+	return s.db.Close()
+}
+
+// Upsert inserts or replaces a Record.
+func (s *VectorStore) Upsert(rec Record) error {
+	embeddingJSON, err := json.Marshal(rec.Embedding)
+	if err != nil {
+		return fmt.Errorf("failed to encode embedding: %w", err)
+	}
+
+	metadataJSON, err := json.Marshal(rec.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO records (id, text, embedding, metadata) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET text = excluded.text, embedding = excluded.embedding, metadata = excluded.metadata`,
+		rec.ID, rec.Text, embeddingJSON, metadataJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert record %s: %w", rec.ID, err)
+	}
+
+	return nil
+}
+
+// TopK returns the k records most similar to query by cosine similarity,
+// highest score first.
+func (s *VectorStore) TopK(query []float32, k int) ([]Scored, error) {
+	rows, err := s.db.Query(`SELECT id, text, embedding, metadata FROM records`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query records: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var scored []Scored
+	for rows.Next() {
+		var id, text string
+		var embeddingJSON, metadataJSON []byte
+
+		if err := rows.Scan(&id, &text, &embeddingJSON, &metadataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan record: %w", err)
+		}
+
+		var embedding []float32
+		if err := json.Unmarshal(embeddingJSON, &embedding); err != nil {
+			continue
+		}
+
+		var metadata map[string]string
+		_ = json.Unmarshal(metadataJSON, &metadata)
+
+		scored = append(scored, Scored{
+			Record: Record{ID: id, Text: text, Embedding: embedding, Metadata: metadata},
+			Score:  cosineSimilarity(query, embedding),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate records: %w", err)
+	}
+
+	sortByScoreDescending(scored)
+
+	if k > 0 && len(scored) > k {
+		scored = scored[:k]
+	}
+
+	return scored, nil
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
+
+func sortByScoreDescending(scored []Scored) {
+	for i := 1; i < len(scored); i++ {
+		for j := i; j > 0 && scored[j].Score > scored[j-1].Score; j-- {
+			scored[j], scored[j-1] = scored[j-1], scored[j]
+		}
+	}
+}